@@ -13,7 +13,38 @@ func Adapt(g *Graph) *state.State {
 
 func adaptAWS(g *Graph) aws.AWS {
 	return aws.AWS{
-		S3:  adaptS3(g),
-		EC2: adaptEC2(g),
+		Meta: aws.Meta{
+			TFProviders: adaptProviders(g),
+		},
+		S3:            adaptS3(g),
+		EC2:           adaptEC2(g),
+		KMS:           adaptKMS(g),
+		IAM:           adaptIAM(g),
+		RDS:           adaptRDS(g),
+		CloudTrail:    adaptCloudTrail(g),
+		CloudWatch:    adaptCloudWatch(g),
+		Lambda:        adaptLambda(g),
+		ECS:           adaptECS(g),
+		ECR:           adaptECR(g),
+		EKS:           adaptEKS(g),
+		DynamoDB:      adaptDynamoDB(g),
+		SNS:           adaptSNS(g),
+		SQS:           adaptSQS(g),
+		ElastiCache:   adaptElastiCache(g),
+		Redshift:      adaptRedshift(g),
+		Elasticsearch: adaptElasticsearch(g),
+		ELB:           adaptELB(g),
+		Cloudfront:    adaptCloudfront(g),
+		APIGateway:    adaptAPIGateway(g),
+		EFS:           adaptEFS(g),
+		Kinesis:       adaptKinesis(g),
+		MSK:           adaptMSK(g),
+		MQ:            adaptMQ(g),
+		Neptune:       adaptNeptune(g),
+		DocumentDB:    adaptDocumentDB(g),
+		Athena:        adaptAthena(g),
+		CodeBuild:     adaptCodeBuild(g),
+		Config:        adaptConfig(g),
+		SSM:           adaptSSM(g),
 	}
 }
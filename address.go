@@ -0,0 +1,58 @@
+package tfplanadapt
+
+import "strings"
+
+// splitAddress splits the resource address or reference into parts separated by dots,
+// the dots inside the instance keys are ignored, e.g. module.nodes["a.b"].aws_instance.this
+func splitAddress(address string) []string {
+	var (
+		parts   []string
+		start   int
+		depth   int
+		inQuote bool
+	)
+
+	for i := 0; i < len(address); i++ {
+		switch c := address[i]; {
+		case inQuote && c == '\\':
+			i++
+		case c == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == '.' && depth == 0:
+			parts = append(parts, address[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, address[start:])
+}
+
+// trimInstanceKey removes the instance key from the part of the address, e.g. this[0] -> this
+func trimInstanceKey(part string) string {
+	if idx := strings.IndexByte(part, '['); idx != -1 {
+		return part[:idx]
+	}
+	return part
+}
+
+// trimModuleKeys removes the instance keys of the modules from the address,
+// e.g. module.nodes["default"].aws_instance.this[0] -> module.nodes.aws_instance.this[0]
+func trimModuleKeys(address string) string {
+	if address == "" {
+		return ""
+	}
+
+	parts := splitAddress(address)
+	for i := 0; i+1 < len(parts); i += 2 {
+		if parts[i] != "module" {
+			break
+		}
+		parts[i+1] = trimInstanceKey(parts[i+1])
+	}
+	return strings.Join(parts, ".")
+}
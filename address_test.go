@@ -0,0 +1,29 @@
+package tfplanadapt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrimModuleKeys(t *testing.T) {
+	tests := []struct {
+		address  string
+		expected string
+	}{
+		{
+			address:  "aws_instance.this[0]",
+			expected: "aws_instance.this[0]",
+		},
+		{
+			address:  `module.nodes["a.b"].module.kms[0].aws_kms_key.this[0]`,
+			expected: "module.nodes.module.kms.aws_kms_key.this[0]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			assert.Equal(t, tt.expected, trimModuleKeys(tt.address))
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package tfplanadapt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/athena"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/documentdb"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/kms"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/neptune"
+	"github.com/aquasecurity/defsec/pkg/state"
+	"github.com/aquasecurity/defsec/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptAnalytics(t *testing.T) {
+
+	const docsKey = "arn:aws:kms:us-east-1:111111111111:key/docs"
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			KMS: kms.KMS{
+				Keys: []kms.Key{
+					{
+						Usage:           types.String("ENCRYPT_DECRYPT", types.Metadata{}),
+						RotationEnabled: types.Bool(true, types.Metadata{}),
+					},
+				},
+			},
+			Neptune: neptune.Neptune{
+				Clusters: []neptune.Cluster{
+					{
+						Logging: neptune.Logging{
+							Audit: types.Bool(true, types.Metadata{}),
+						},
+						StorageEncrypted: types.Bool(true, types.Metadata{}),
+						KMSKeyID:         types.StringUnresolvable(types.Metadata{}),
+					},
+					{},
+				},
+			},
+			DocumentDB: documentdb.DocumentDB{
+				Clusters: []documentdb.Cluster{
+					{
+						Identifier: types.String("docs", types.Metadata{}),
+						EnabledLogExports: []types.StringValue{
+							types.String(documentdb.LogExportAudit, types.Metadata{}),
+							types.String(documentdb.LogExportProfiler, types.Metadata{}),
+						},
+						BackupRetentionPeriod: types.Int(7, types.Metadata{}),
+						StorageEncrypted:      types.Bool(true, types.Metadata{}),
+						KMSKeyID:              types.String(docsKey, types.Metadata{}),
+						Instances: []documentdb.Instance{
+							{
+								KMSKeyID: types.String(docsKey, types.Metadata{}),
+							},
+						},
+					},
+					{
+						Identifier:            types.String("external", types.Metadata{}),
+						BackupRetentionPeriod: types.IntUnresolvable(types.Metadata{}),
+						StorageEncrypted:      types.BoolUnresolvable(types.Metadata{}),
+						KMSKeyID:              types.StringUnresolvable(types.Metadata{}),
+						Instances: []documentdb.Instance{
+							{
+								KMSKeyID: types.StringUnresolvable(types.Metadata{}),
+							},
+						},
+					},
+				},
+			},
+			Athena: athena.Athena{
+				Databases: []athena.Database{
+					{
+						Name: types.String("reports", types.Metadata{}),
+						Encryption: athena.EncryptionConfiguration{
+							Type: types.String(athena.EncryptionTypeSSEKMS, types.Metadata{}),
+						},
+					},
+					{
+						Name: types.String("scratch", types.Metadata{}),
+					},
+				},
+				Workgroups: []athena.Workgroup{
+					{
+						Name:                 types.String("adhoc", types.Metadata{}),
+						EnforceConfiguration: types.Bool(true, types.Metadata{}),
+					},
+					{
+						Name: types.String("primary", types.Metadata{}),
+						Encryption: athena.EncryptionConfiguration{
+							Type: types.String(athena.EncryptionTypeSSES3, types.Metadata{}),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got := runAdaptTest(t, filepath.Join("testdata", "analytics", "tfplan.json"), expected)
+
+	// the cluster is encrypted with the key created by the plan
+	assert.False(t, got.AWS.Neptune.Clusters[0].KMSKeyID.IsEmpty())
+	assert.True(t, got.AWS.Neptune.Clusters[1].KMSKeyID.IsEmpty())
+
+	// the external cluster is not managed by the plan, so its settings are not known
+	external := got.AWS.DocumentDB.Clusters[1]
+	assert.True(t, external.Metadata.IsUnmanaged())
+	assert.Equal(t, "aws_docdb_cluster_instance.external", external.Instances[0].Metadata.Reference())
+	assert.False(t, external.StorageEncrypted.GetMetadata().IsResolvable())
+	for _, result := range Scan(got) {
+		assert.NotEqual(t, "aws_docdb_cluster_instance.external", result.Metadata().Reference(), result.Rule().AVDID)
+	}
+}
@@ -0,0 +1,238 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/apigateway"
+	v1 "github.com/aquasecurity/defsec/pkg/providers/aws/apigateway/v1"
+	v2 "github.com/aquasecurity/defsec/pkg/providers/aws/apigateway/v2"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptAPIGateway(g *Graph) apigateway.APIGateway {
+	return apigateway.APIGateway{
+		V1: v1.APIGateway{
+			APIs:        adaptRESTAPIs(g),
+			DomainNames: adaptRESTDomainNames(g),
+		},
+		V2: v2.APIGateway{
+			APIs:        adaptAPIsV2(g),
+			DomainNames: adaptDomainNamesV2(g),
+		},
+	}
+}
+
+func adaptRESTAPIs(g *Graph) []v1.API {
+	adoptedStages := make(map[string]struct{})
+	adoptedMethods := make(map[string]struct{})
+
+	var apis []v1.API
+	for _, res := range g.FindResourcesByType("aws_api_gateway_rest_api") {
+		api := v1.API{
+			Metadata: res.Metadata(),
+			Name:     res.GetStringAttr("name"),
+		}
+
+		for _, stage := range res.FindAllBackRelatedOrMatching(g, "aws_api_gateway_stage", "rest_api_id", "id") {
+			api.Stages = append(api.Stages, adaptRESTStage(g, res, stage))
+			adoptedStages[stage.ID()] = struct{}{}
+		}
+
+		methods := res.FindAllBackRelatedOrMatching(g, "aws_api_gateway_method", "rest_api_id", "id")
+		api.Resources = adaptRESTResources(res, methods)
+		for _, method := range methods {
+			adoptedMethods[method.ID()] = struct{}{}
+		}
+
+		apis = append(apis, api)
+	}
+
+	// stages and methods of APIs that are not managed by the plan
+	for _, stage := range g.FindResourcesByType("aws_api_gateway_stage") {
+		if _, adopted := adoptedStages[stage.ID()]; adopted {
+			continue
+		}
+		// only the stage is managed by the plan
+		metadata := types.NewUnmanagedMetadata()
+		apis = append(apis, v1.API{
+			Metadata: metadata,
+			Name:     types.StringDefault("", metadata),
+			Stages:   []v1.Stage{adaptRESTStage(g, nil, stage)},
+		})
+	}
+
+	for _, method := range g.FindResourcesByType("aws_api_gateway_method") {
+		if _, adopted := adoptedMethods[method.ID()]; adopted {
+			continue
+		}
+		// only the method is managed by the plan
+		metadata := types.NewUnmanagedMetadata()
+		apis = append(apis, v1.API{
+			Metadata: metadata,
+			Name:     types.StringDefault("", metadata),
+			Resources: []v1.Resource{
+				{
+					Metadata: metadata,
+					Methods:  []v1.Method{adaptRESTMethod(method)},
+				},
+			},
+		})
+	}
+
+	return apis
+}
+
+// adaptRESTStage adapts the stage with the method settings of the stage,
+// the settings are related to the stage by the stage name and the API
+func adaptRESTStage(g *Graph, api, res *Node) v1.Stage {
+	stage := v1.Stage{
+		Metadata: res.Metadata(),
+		Name:     res.GetStringAttr("stage_name"),
+		AccessLogging: v1.AccessLogging{
+			Metadata:              res.Metadata(),
+			CloudwatchLogGroupARN: getAccessLogDestination(res),
+		},
+		XRayTracingEnabled: res.GetBoolAttr("xray_tracing_enabled"),
+	}
+
+	methodSettings := res.FindAllBackRelated("aws_api_gateway_method_settings", "stage_name", "stage_name")
+	if api != nil && stage.Name.IsNotEmpty() {
+		for _, settings := range api.FindAllBackRelatedOrMatching(
+			g, "aws_api_gateway_method_settings", "rest_api_id", "id",
+		) {
+			if settings.GetStringAttr("stage_name").EqualTo(stage.Name.Value()) && !containsNode(methodSettings, settings) {
+				methodSettings = append(methodSettings, settings)
+			}
+		}
+	}
+
+	for _, settings := range methodSettings {
+		stage.RESTMethodSettings = append(stage.RESTMethodSettings, v1.RESTMethodSettings{
+			Metadata:           settings.Metadata(),
+			Method:             settings.GetStringAttr("method_path"),
+			CacheDataEncrypted: settings.GetAttr("settings").GetBoolAttr("cache_data_encrypted"),
+			CacheEnabled:       settings.GetAttr("settings").GetBoolAttr("caching_enabled"),
+		})
+	}
+
+	return stage
+}
+
+// getAccessLogDestination returns the ARN of the log group the access logs are sent to
+func getAccessLogDestination(res *Node) types.StringValue {
+	if destination := res.GetAttr("access_log_settings").GetStringAttr("destination_arn"); destination.IsNotEmpty() {
+		return destination
+	}
+	if logGroup := res.FindRelated("aws_cloudwatch_log_group", "access_log_settings.destination_arn", "arn"); logGroup != nil {
+		return getLogGroupArn(logGroup)
+	}
+	return res.getReferencedStringAttr("access_log_settings.destination_arn")
+}
+
+// adaptRESTResources groups the methods of the API by the resources they belong to.
+// The methods of the root resource and of resources that are not known belong to the API
+func adaptRESTResources(api *Node, methods []*Node) []v1.Resource {
+	var (
+		resources []v1.Resource
+		indexes   = make(map[string]int)
+	)
+
+	for _, method := range methods {
+		metadata := api.Metadata()
+		if resource := method.FindRelated("aws_api_gateway_resource", "resource_id", "id"); resource != nil {
+			metadata = resource.Metadata()
+		}
+
+		idx, exists := indexes[metadata.Reference()]
+		if !exists {
+			idx = len(resources)
+			indexes[metadata.Reference()] = idx
+			resources = append(resources, v1.Resource{
+				Metadata: metadata,
+			})
+		}
+		resources[idx].Methods = append(resources[idx].Methods, adaptRESTMethod(method))
+	}
+
+	return resources
+}
+
+func adaptRESTMethod(res *Node) v1.Method {
+	return v1.Method{
+		Metadata:          res.Metadata(),
+		HTTPMethod:        res.GetStringAttr("http_method"),
+		AuthorizationType: res.GetStringAttr("authorization"),
+		APIKeyRequired:    res.GetBoolAttr("api_key_required"),
+	}
+}
+
+func adaptRESTDomainNames(g *Graph) []v1.DomainName {
+	var domainNames []v1.DomainName
+	for _, res := range g.FindResourcesByType("aws_api_gateway_domain_name") {
+		domainNames = append(domainNames, v1.DomainName{
+			Metadata: res.Metadata(),
+			Name:     res.GetStringAttr("domain_name"),
+			// TLS 1.0 is used by default
+			SecurityPolicy: res.GetStringAttr("security_policy", "TLS_1_0"),
+		})
+	}
+	return domainNames
+}
+
+func adaptAPIsV2(g *Graph) []v2.API {
+	adoptedStages := make(map[string]struct{})
+
+	var apis []v2.API
+	for _, res := range g.FindResourcesByType("aws_apigatewayv2_api") {
+		api := v2.API{
+			Metadata:     res.Metadata(),
+			Name:         res.GetStringAttr("name"),
+			ProtocolType: res.GetStringAttr("protocol_type"),
+		}
+
+		for _, stage := range res.FindAllBackRelatedOrMatching(g, "aws_apigatewayv2_stage", "api_id", "id") {
+			api.Stages = append(api.Stages, adaptStageV2(stage))
+			adoptedStages[stage.ID()] = struct{}{}
+		}
+
+		apis = append(apis, api)
+	}
+
+	// stages of APIs that are not managed by the plan
+	for _, stage := range g.FindResourcesByType("aws_apigatewayv2_stage") {
+		if _, adopted := adoptedStages[stage.ID()]; adopted {
+			continue
+		}
+		// only the stage is managed by the plan
+		metadata := types.NewUnmanagedMetadata()
+		apis = append(apis, v2.API{
+			Metadata:     metadata,
+			Name:         types.StringDefault("", metadata),
+			ProtocolType: types.StringDefault(v2.ProtocolTypeUnknown, metadata),
+			Stages:       []v2.Stage{adaptStageV2(stage)},
+		})
+	}
+
+	return apis
+}
+
+func adaptStageV2(res *Node) v2.Stage {
+	return v2.Stage{
+		Metadata: res.Metadata(),
+		Name:     res.GetStringAttr("name"),
+		AccessLogging: v2.AccessLogging{
+			Metadata:              res.Metadata(),
+			CloudwatchLogGroupARN: getAccessLogDestination(res),
+		},
+	}
+}
+
+func adaptDomainNamesV2(g *Graph) []v2.DomainName {
+	var domainNames []v2.DomainName
+	for _, res := range g.FindResourcesByType("aws_apigatewayv2_domain_name") {
+		domainNames = append(domainNames, v2.DomainName{
+			Metadata:       res.Metadata(),
+			Name:           res.GetStringAttr("domain_name"),
+			SecurityPolicy: res.GetAttr("domain_name_configuration").GetStringAttr("security_policy"),
+		})
+	}
+	return domainNames
+}
@@ -0,0 +1,149 @@
+package tfplanadapt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/apigateway"
+	v1 "github.com/aquasecurity/defsec/pkg/providers/aws/apigateway/v1"
+	v2 "github.com/aquasecurity/defsec/pkg/providers/aws/apigateway/v2"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/cloudwatch"
+	"github.com/aquasecurity/defsec/pkg/state"
+	"github.com/aquasecurity/defsec/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptAPIGateway(t *testing.T) {
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			CloudWatch: cloudwatch.CloudWatch{
+				LogGroups: []cloudwatch.LogGroup{
+					{
+						Arn:  types.StringUnresolvable(types.Metadata{}),
+						Name: types.String("api-access", types.Metadata{}),
+					},
+				},
+			},
+			APIGateway: apigateway.APIGateway{
+				V1: v1.APIGateway{
+					APIs: []v1.API{
+						{
+							Name: types.String("main", types.Metadata{}),
+							Stages: []v1.Stage{
+								{
+									Name: types.String("prod", types.Metadata{}),
+									AccessLogging: v1.AccessLogging{
+										CloudwatchLogGroupARN: types.StringUnresolvable(types.Metadata{}),
+									},
+									XRayTracingEnabled: types.Bool(true, types.Metadata{}),
+									RESTMethodSettings: []v1.RESTMethodSettings{
+										{
+											Method:             types.String("*/*", types.Metadata{}),
+											CacheDataEncrypted: types.Bool(true, types.Metadata{}),
+											CacheEnabled:       types.Bool(true, types.Metadata{}),
+										},
+									},
+								},
+							},
+							Resources: []v1.Resource{
+								{
+									Methods: []v1.Method{
+										{
+											HTTPMethod:        types.String("GET", types.Metadata{}),
+											AuthorizationType: types.String(v1.AuthorizationNone, types.Metadata{}),
+										},
+									},
+								},
+								{
+									Methods: []v1.Method{
+										{
+											HTTPMethod:        types.String("ANY", types.Metadata{}),
+											AuthorizationType: types.String(v1.AuthorizationIAM, types.Metadata{}),
+											APIKeyRequired:    types.Bool(true, types.Metadata{}),
+										},
+									},
+								},
+							},
+						},
+						{
+							Stages: []v1.Stage{
+								{
+									Name: types.String("dev", types.Metadata{}),
+								},
+							},
+						},
+						{
+							Resources: []v1.Resource{
+								{
+									Methods: []v1.Method{
+										{
+											HTTPMethod:        types.String("POST", types.Metadata{}),
+											AuthorizationType: types.String(v1.AuthorizationNone, types.Metadata{}),
+										},
+									},
+								},
+							},
+						},
+					},
+					DomainNames: []v1.DomainName{
+						{
+							Name:           types.String("api.example.com", types.Metadata{}),
+							SecurityPolicy: types.String("TLS_1_2", types.Metadata{}),
+						},
+					},
+				},
+				V2: v2.APIGateway{
+					APIs: []v2.API{
+						{
+							Name:         types.String("http", types.Metadata{}),
+							ProtocolType: types.String(v2.ProtocolTypeHTTP, types.Metadata{}),
+							Stages: []v2.Stage{
+								{
+									Name: types.String("$default", types.Metadata{}),
+								},
+							},
+						},
+						{
+							ProtocolType: types.String(v2.ProtocolTypeUnknown, types.Metadata{}),
+							Stages: []v2.Stage{
+								{
+									Name: types.String("dev", types.Metadata{}),
+								},
+							},
+						},
+					},
+					DomainNames: []v2.DomainName{
+						{
+							Name:           types.String("http.example.com", types.Metadata{}),
+							SecurityPolicy: types.String("TLS_1_2", types.Metadata{}),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got := runAdaptTest(t, filepath.Join("testdata", "apigateway", "tfplan.json"), expected)
+
+	// the methods are grouped by the resources they belong to
+	resources := got.AWS.APIGateway.V1.APIs[0].Resources
+	assert.Equal(t, "aws_api_gateway_resource.users", resources[0].Metadata.Reference())
+	assert.Equal(t, "aws_api_gateway_rest_api.main", resources[1].Metadata.Reference())
+
+	// the APIs of the external stages and methods are not managed by the plan, only their children are
+	externalStage := got.AWS.APIGateway.V1.APIs[1]
+	assert.True(t, externalStage.Metadata.IsUnmanaged())
+	assert.Equal(t, "aws_api_gateway_stage.external", externalStage.Stages[0].Metadata.Reference())
+
+	externalMethod := got.AWS.APIGateway.V1.APIs[2]
+	assert.True(t, externalMethod.Metadata.IsUnmanaged())
+	assert.True(t, externalMethod.Resources[0].Metadata.IsUnmanaged())
+	assert.Equal(t, "aws_api_gateway_method.external", externalMethod.Resources[0].Methods[0].Metadata.Reference())
+
+	externalStageV2 := got.AWS.APIGateway.V2.APIs[1]
+	assert.True(t, externalStageV2.Metadata.IsUnmanaged())
+	assert.Equal(t, "aws_apigatewayv2_stage.external", externalStageV2.Stages[0].Metadata.Reference())
+}
@@ -0,0 +1,54 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/athena"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptAthena(g *Graph) athena.Athena {
+	var databases []athena.Database
+	for _, res := range g.FindResourcesByType("aws_athena_database") {
+		databases = append(databases, athena.Database{
+			Metadata:   res.Metadata(),
+			Name:       res.GetStringAttr("name"),
+			Encryption: adaptAthenaEncryption(res, res.GetAttr("encryption_configuration")),
+		})
+	}
+
+	var workgroups []athena.Workgroup
+	for _, res := range g.FindResourcesByType("aws_athena_workgroup") {
+		configuration := res.GetAttr("configuration")
+		workgroup := athena.Workgroup{
+			Metadata: res.Metadata(),
+			Name:     res.GetStringAttr("name"),
+			Encryption: adaptAthenaEncryption(
+				res, configuration.GetNestedAttr("result_configuration.encryption_configuration"),
+			),
+			EnforceConfiguration: types.BoolDefault(true, res.Metadata()),
+		}
+
+		if enforce := configuration.GetNestedAttr("enforce_workgroup_configuration").AsBool(); enforce != nil {
+			workgroup.EnforceConfiguration = types.Bool(*enforce, res.Metadata())
+		}
+
+		workgroups = append(workgroups, workgroup)
+	}
+
+	return athena.Athena{
+		Databases:  databases,
+		Workgroups: workgroups,
+	}
+}
+
+func adaptAthenaEncryption(res *Node, encryptionConfiguration *Attribute) athena.EncryptionConfiguration {
+	encryption := athena.EncryptionConfiguration{
+		Metadata: res.Metadata(),
+		Type:     types.StringDefault(athena.EncryptionTypeNone, res.Metadata()),
+	}
+
+	if option := encryptionConfiguration.GetNestedAttr("encryption_option").AsString(); option != nil {
+		encryption.Type = types.String(*option, res.Metadata())
+	}
+
+	return encryption
+}
@@ -7,7 +7,16 @@ import (
 )
 
 type Attribute struct {
-	val any
+	val      any
+	metadata defsecTypes.Metadata
+}
+
+// Metadata returns the metadata of the resource that owns the attribute
+func (a *Attribute) Metadata() defsecTypes.Metadata {
+	if a == nil {
+		return defsecTypes.Metadata{}
+	}
+	return a.metadata
 }
 
 func (a *Attribute) ToList() []*Attribute {
@@ -26,7 +35,7 @@ func (a *Attribute) ToList() []*Attribute {
 			continue
 		}
 
-		res = append(res, &Attribute{m})
+		res = append(res, &Attribute{val: m, metadata: a.metadata})
 	}
 
 	return res
@@ -43,17 +52,17 @@ func (a *Attribute) GetNestedAttr(path string) *Attribute {
 
 	if val, ok := a.val.([]any); ok {
 		if len(val) == 0 {
-			return nil
+			return &Attribute{metadata: a.metadata}
 		}
 		m, ok = val[0].(map[string]any)
 		if !ok {
-			return nil
+			return &Attribute{metadata: a.metadata}
 		}
 	} else if val, ok := a.val.(map[string]any); ok {
 		m = val
 	}
 
-	attr := &Attribute{m[parts[0]]}
+	attr := &Attribute{val: m[parts[0]], metadata: a.metadata}
 
 	if len(parts) == 1 {
 		return attr
@@ -63,7 +72,7 @@ func (a *Attribute) GetNestedAttr(path string) *Attribute {
 }
 
 func (a *Attribute) GetStringAttr(path string) defsecTypes.StringValue {
-	def := defsecTypes.StringDefault("", defsecTypes.Metadata{})
+	def := defsecTypes.StringDefault("", a.Metadata())
 	if a.IsNil() {
 		return def
 	}
@@ -73,11 +82,11 @@ func (a *Attribute) GetStringAttr(path string) defsecTypes.StringValue {
 	if val == nil {
 		return def
 	}
-	return defsecTypes.String(*val, defsecTypes.Metadata{})
+	return defsecTypes.String(*val, a.metadata)
 }
 
 func (a *Attribute) GetBoolAttr(path string) defsecTypes.BoolValue {
-	def := defsecTypes.BoolDefault(false, defsecTypes.Metadata{})
+	def := defsecTypes.BoolDefault(false, a.Metadata())
 	if a.IsNil() {
 		return def
 	}
@@ -88,7 +97,22 @@ func (a *Attribute) GetBoolAttr(path string) defsecTypes.BoolValue {
 		return def
 	}
 
-	return defsecTypes.Bool(*val, defsecTypes.Metadata{})
+	return defsecTypes.Bool(*val, a.metadata)
+}
+
+func (a *Attribute) GetIntAttr(path string) defsecTypes.IntValue {
+	def := defsecTypes.IntDefault(0, a.Metadata())
+	if a.IsNil() {
+		return def
+	}
+
+	nested := a.GetNestedAttr(path)
+	val := nested.AsInt()
+	if val == nil {
+		return def
+	}
+
+	return defsecTypes.Int(*val, a.metadata)
 }
 
 // TODO
@@ -116,6 +140,60 @@ func (a *Attribute) AsString() *string {
 	return &val
 }
 
+// AsStrings returns the string elements of the list or set value
+func (a *Attribute) AsStrings() []string {
+	if a.IsNil() {
+		return nil
+	}
+
+	val, ok := a.val.([]any)
+	if !ok {
+		return nil
+	}
+
+	res := make([]string, 0, len(val))
+	for _, el := range val {
+		if s, ok := el.(string); ok {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// AsStringMap returns the string values of the map value, e.g. tags
+func (a *Attribute) AsStringMap() map[string]string {
+	if a.IsNil() {
+		return nil
+	}
+
+	val, ok := a.val.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	res := make(map[string]string, len(val))
+	for k, el := range val {
+		if s, ok := el.(string); ok {
+			res[k] = s
+		}
+	}
+	return res
+}
+
+// AsInt returns the number value as an int, numbers are decoded from JSON as float64
+func (a *Attribute) AsInt() *int {
+	if a.IsNil() {
+		return nil
+	}
+
+	val, ok := a.val.(float64)
+	if !ok {
+		return nil
+	}
+	res := int(val)
+	return &res
+}
+
 func (a *Attribute) IsNil() bool {
 	return a == nil || a.val == nil
 }
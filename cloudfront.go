@@ -0,0 +1,50 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/cloudfront"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptCloudfront(g *Graph) cloudfront.Cloudfront {
+	var distributions []cloudfront.Distribution
+	for _, res := range g.FindResourcesByType("aws_cloudfront_distribution") {
+		viewerCertificate := res.GetAttr("viewer_certificate")
+
+		distribution := cloudfront.Distribution{
+			Metadata: res.Metadata(),
+			WAFID:    res.getReferencedStringAttr("web_acl_id"),
+			Logging: cloudfront.Logging{
+				Metadata: res.Metadata(),
+				Bucket:   res.getReferencedStringAttr("logging_config.bucket"),
+			},
+			DefaultCacheBehaviour: cloudfront.CacheBehaviour{
+				Metadata:             res.Metadata(),
+				ViewerProtocolPolicy: res.GetAttr("default_cache_behavior").GetStringAttr("viewer_protocol_policy"),
+			},
+			ViewerCertificate: cloudfront.ViewerCertificate{
+				Metadata:                     res.Metadata(),
+				CloudfrontDefaultCertificate: viewerCertificate.GetBoolAttr("cloudfront_default_certificate"),
+				SSLSupportMethod:             viewerCertificate.GetStringAttr("ssl_support_method"),
+				MinimumProtocolVersion:       viewerCertificate.GetStringAttr("minimum_protocol_version"),
+			},
+		}
+
+		// TLSv1 is used by default
+		if distribution.ViewerCertificate.MinimumProtocolVersion.IsEmpty() {
+			distribution.ViewerCertificate.MinimumProtocolVersion = types.StringDefault("TLSv1", res.Metadata())
+		}
+
+		for _, behaviour := range res.GetAttr("ordered_cache_behavior").ToList() {
+			distribution.OrdererCacheBehaviours = append(distribution.OrdererCacheBehaviours, cloudfront.CacheBehaviour{
+				Metadata:             behaviour.Metadata(),
+				ViewerProtocolPolicy: behaviour.GetStringAttr("viewer_protocol_policy"),
+			})
+		}
+
+		distributions = append(distributions, distribution)
+	}
+
+	return cloudfront.Cloudfront{
+		Distributions: distributions,
+	}
+}
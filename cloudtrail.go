@@ -0,0 +1,66 @@
+package tfplanadapt
+
+import (
+	"strings"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws/cloudtrail"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptCloudTrail(g *Graph) cloudtrail.CloudTrail {
+	var trails []cloudtrail.Trail
+	for _, res := range g.FindResourcesByType("aws_cloudtrail") {
+		trail := cloudtrail.Trail{
+			Metadata:                  res.Metadata(),
+			Name:                      res.GetStringAttr("name"),
+			EnableLogFileValidation:   res.GetBoolAttr("enable_log_file_validation"),
+			IsMultiRegion:             res.GetBoolAttr("is_multi_region_trail"),
+			KMSKeyID:                  getKMSKeyID(g, res, "kms_key_id"),
+			CloudWatchLogsLogGroupArn: res.GetStringAttr("cloud_watch_logs_group_arn"),
+			IsLogging:                 res.GetBoolAttr("enable_logging", true),
+			BucketName:                res.GetStringAttr("s3_bucket_name"),
+		}
+
+		// the log group ARN of the trail has the ":*" suffix
+		if logGroupArn := trail.CloudWatchLogsLogGroupArn; logGroupArn.IsNotEmpty() {
+			trail.CloudWatchLogsLogGroupArn = types.String(strings.TrimSuffix(logGroupArn.Value(), ":*"), res.Metadata())
+		} else if logGroup := res.FindRelated(
+			"aws_cloudwatch_log_group", "cloud_watch_logs_group_arn", "arn",
+		); logGroup != nil {
+			trail.CloudWatchLogsLogGroupArn = getLogGroupArn(logGroup)
+		}
+
+		if bucket := res.FindRelated("aws_s3_bucket", "s3_bucket_name", "bucket", "id"); bucket != nil {
+			trail.BucketName = bucket.GetStringAttr("bucket", bucket.ID())
+		}
+
+		for _, eventSelector := range res.GetAttr("event_selector").ToList() {
+			selector := cloudtrail.EventSelector{
+				Metadata:      res.Metadata(),
+				ReadWriteType: eventSelector.GetStringAttr("read_write_type"),
+			}
+			if selector.ReadWriteType.IsEmpty() {
+				selector.ReadWriteType = types.StringDefault("All", res.Metadata())
+			}
+
+			for _, dataResource := range eventSelector.GetNestedAttr("data_resource").ToList() {
+				resource := cloudtrail.DataResource{
+					Metadata: res.Metadata(),
+					Type:     dataResource.GetStringAttr("type"),
+				}
+				for _, value := range dataResource.GetNestedAttr("values").AsStrings() {
+					resource.Values = append(resource.Values, types.String(value, res.Metadata()))
+				}
+				selector.DataResources = append(selector.DataResources, resource)
+			}
+
+			trail.EventSelectors = append(trail.EventSelectors, selector)
+		}
+
+		trails = append(trails, trail)
+	}
+
+	return cloudtrail.CloudTrail{
+		Trails: trails,
+	}
+}
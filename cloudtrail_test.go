@@ -0,0 +1,97 @@
+package tfplanadapt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/cloudtrail"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/cloudwatch"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/s3"
+	"github.com/aquasecurity/defsec/pkg/state"
+	"github.com/aquasecurity/defsec/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptCloudTrail(t *testing.T) {
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			S3: s3.S3{
+				Buckets: []s3.Bucket{
+					{
+						Name: types.String("trail-logs", types.Metadata{}),
+					},
+				},
+			},
+			CloudTrail: cloudtrail.CloudTrail{
+				Trails: []cloudtrail.Trail{
+					{
+						Name:                      types.String("main", types.Metadata{}),
+						EnableLogFileValidation:   types.Bool(true, types.Metadata{}),
+						IsMultiRegion:             types.Bool(true, types.Metadata{}),
+						CloudWatchLogsLogGroupArn: types.StringUnresolvable(types.Metadata{}),
+						IsLogging:                 types.Bool(true, types.Metadata{}),
+						BucketName:                types.String("trail-logs", types.Metadata{}),
+						EventSelectors: []cloudtrail.EventSelector{
+							{
+								ReadWriteType: types.String("WriteOnly", types.Metadata{}),
+								DataResources: []cloudtrail.DataResource{
+									{
+										Type: types.String("AWS::S3::Object", types.Metadata{}),
+										Values: []types.StringValue{
+											types.String("arn:aws:s3", types.Metadata{}),
+										},
+									},
+								},
+							},
+						},
+					},
+					{
+						Name:       types.String("regional", types.Metadata{}),
+						IsLogging:  types.Bool(false, types.Metadata{}),
+						BucketName: types.String("external-bucket", types.Metadata{}),
+					},
+				},
+			},
+			CloudWatch: cloudwatch.CloudWatch{
+				LogGroups: []cloudwatch.LogGroup{
+					{
+						Arn:             types.StringUnresolvable(types.Metadata{}),
+						Name:            types.String("trail", types.Metadata{}),
+						RetentionInDays: types.Int(365, types.Metadata{}),
+						MetricFilters: []cloudwatch.MetricFilter{
+							{
+								FilterName: types.String("CloudTrailChanges", types.Metadata{}),
+								FilterPattern: types.String(
+									"{($.eventName=CreateTrail) || ($.eventName=UpdateTrail) || ($.eventName=DeleteTrail) || ($.eventName=StartLogging) || ($.eventName=StopLogging)}",
+									types.Metadata{},
+								),
+							},
+						},
+					},
+				},
+				Alarms: []cloudwatch.Alarm{
+					{
+						AlarmName:  types.String("trail-changes", types.Metadata{}),
+						MetricName: types.String("CloudTrailChanges", types.Metadata{}),
+						Dimensions: []cloudwatch.AlarmDimension{
+							{
+								Name:  types.String("Trail", types.Metadata{}),
+								Value: types.String("main", types.Metadata{}),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got := runAdaptTest(t, filepath.Join("testdata", "cloudtrail", "tfplan.json"), expected)
+
+	// the trail refers to the log group created by the plan, its ARN is not known until apply
+	trail := got.AWS.CloudTrail.Trails[0]
+	assert.False(t, trail.CloudWatchLogsLogGroupArn.GetMetadata().IsResolvable())
+	assert.Equal(t, "aws_cloudwatch_log_group.trail", trail.CloudWatchLogsLogGroupArn.GetMetadata().Reference())
+}
@@ -0,0 +1,88 @@
+package tfplanadapt
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws/cloudwatch"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptCloudWatch(g *Graph) cloudwatch.CloudWatch {
+	return cloudwatch.CloudWatch{
+		LogGroups: adaptLogGroups(g),
+		Alarms:    adaptAlarms(g),
+	}
+}
+
+func adaptLogGroups(g *Graph) []cloudwatch.LogGroup {
+	var logGroups []cloudwatch.LogGroup
+	for _, res := range g.FindResourcesByType("aws_cloudwatch_log_group") {
+		logGroup := cloudwatch.LogGroup{
+			Metadata:        res.Metadata(),
+			Arn:             getLogGroupArn(res),
+			Name:            res.GetStringAttr("name"),
+			KMSKeyID:        getKMSKeyID(g, res, "kms_key_id"),
+			RetentionInDays: res.GetIntAttr("retention_in_days"),
+		}
+
+		for _, metricFilter := range res.FindAllBackRelatedOrMatching(
+			g, "aws_cloudwatch_log_metric_filter", "log_group_name", "name", "id",
+		) {
+			logGroup.MetricFilters = append(logGroup.MetricFilters, cloudwatch.MetricFilter{
+				Metadata:      metricFilter.Metadata(),
+				FilterName:    metricFilter.GetStringAttr("name"),
+				FilterPattern: metricFilter.GetStringAttr("pattern"),
+			})
+		}
+
+		logGroups = append(logGroups, logGroup)
+	}
+	return logGroups
+}
+
+// getLogGroupArn returns the ARN of the log group. If the log group is created by the plan,
+// its ARN is not known until apply and the value is unresolvable
+func getLogGroupArn(res *Node) types.StringValue {
+	if arn := res.GetStringAttr("arn"); arn.IsNotEmpty() {
+		return types.String(strings.TrimSuffix(arn.Value(), ":*"), res.Metadata())
+	}
+	return types.StringUnresolvable(res.Metadata())
+}
+
+func adaptAlarms(g *Graph) []cloudwatch.Alarm {
+	var alarms []cloudwatch.Alarm
+	for _, res := range g.FindResourcesByType("aws_cloudwatch_metric_alarm") {
+		alarm := cloudwatch.Alarm{
+			Metadata:   res.Metadata(),
+			AlarmName:  res.GetStringAttr("alarm_name"),
+			MetricName: res.GetStringAttr("metric_name"),
+		}
+
+		dimensions := res.GetAttr("dimensions").AsStringMap()
+		names := make([]string, 0, len(dimensions))
+		for name := range dimensions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			alarm.Dimensions = append(alarm.Dimensions, cloudwatch.AlarmDimension{
+				Metadata: res.Metadata(),
+				Name:     types.String(name, res.Metadata()),
+				Value:    types.String(dimensions[name], res.Metadata()),
+			})
+		}
+
+		for _, query := range res.GetAttr("metric_query").ToList() {
+			alarm.Metrics = append(alarm.Metrics, cloudwatch.MetricDataQuery{
+				Metadata:   res.Metadata(),
+				Expression: query.GetStringAttr("expression"),
+				ID:         query.GetStringAttr("id"),
+			})
+		}
+
+		alarms = append(alarms, alarm)
+	}
+	return alarms
+}
@@ -0,0 +1,43 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/codebuild"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptCodeBuild(g *Graph) codebuild.CodeBuild {
+	var projects []codebuild.Project
+	for _, res := range g.FindResourcesByType("aws_codebuild_project") {
+		project := codebuild.Project{
+			Metadata:         res.Metadata(),
+			ArtifactSettings: adaptArtifactSettings(res, res.GetAttr("artifacts")),
+		}
+
+		for _, artifacts := range res.GetAttr("secondary_artifacts").ToList() {
+			project.SecondaryArtifactSettings = append(
+				project.SecondaryArtifactSettings, adaptArtifactSettings(res, artifacts),
+			)
+		}
+
+		projects = append(projects, project)
+	}
+
+	return codebuild.CodeBuild{
+		Projects: projects,
+	}
+}
+
+// adaptArtifactSettings returns the settings of the artifacts, the artifacts are encrypted
+// unless the encryption is explicitly disabled
+func adaptArtifactSettings(res *Node, artifacts *Attribute) codebuild.ArtifactSettings {
+	settings := codebuild.ArtifactSettings{
+		Metadata:          res.Metadata(),
+		EncryptionEnabled: types.BoolDefault(true, res.Metadata()),
+	}
+
+	if disabled := artifacts.GetNestedAttr("encryption_disabled").AsBool(); disabled != nil {
+		settings.EncryptionEnabled = types.Bool(!*disabled, res.Metadata())
+	}
+
+	return settings
+}
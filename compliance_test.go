@@ -0,0 +1,72 @@
+package tfplanadapt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/codebuild"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/config"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/kms"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/ssm"
+	"github.com/aquasecurity/defsec/pkg/state"
+	"github.com/aquasecurity/defsec/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptCompliance(t *testing.T) {
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			KMS: kms.KMS{
+				Keys: []kms.Key{
+					{
+						Usage:           types.String("ENCRYPT_DECRYPT", types.Metadata{}),
+						RotationEnabled: types.Bool(true, types.Metadata{}),
+					},
+				},
+			},
+			CodeBuild: codebuild.CodeBuild{
+				Projects: []codebuild.Project{
+					{
+						ArtifactSettings: codebuild.ArtifactSettings{
+							EncryptionEnabled: types.Bool(true, types.Metadata{}),
+						},
+						SecondaryArtifactSettings: []codebuild.ArtifactSettings{
+							{},
+						},
+					},
+				},
+			},
+			Config: config.Config{
+				ConfigurationAggregrator: config.ConfigurationAggregrator{
+					SourceAllRegions: types.Bool(false, types.Metadata{}),
+				},
+			},
+			SSM: ssm.SSM{
+				Secrets: []ssm.Secret{
+					{
+						KMSKeyID: types.StringUnresolvable(types.Metadata{}),
+					},
+					{
+						KMSKeyID: types.String(ssm.DefaultKMSKeyID, types.Metadata{}),
+					},
+					{
+						KMSKeyID: types.StringUnresolvable(types.Metadata{}),
+					},
+				},
+			},
+		},
+	}
+
+	got := runAdaptTest(t, filepath.Join("testdata", "compliance", "tfplan.json"), expected)
+
+	// the secret and the parameter are encrypted with the key created by the plan
+	assert.False(t, got.AWS.SSM.Secrets[0].KMSKeyID.IsEmpty())
+	assert.Equal(t, "aws_ssm_parameter.token", got.AWS.SSM.Secrets[2].Metadata.Reference())
+	assert.True(t, got.AWS.Config.ConfigurationAggregrator.Metadata.IsManaged())
+	// the aggregator that misses regions is adapted regardless of the order of the resources
+	assert.Equal(t, "aws_config_configuration_aggregator.account",
+		got.AWS.Config.ConfigurationAggregrator.Metadata.Reference())
+}
@@ -0,0 +1,41 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/config"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptConfig(g *Graph) config.Config {
+	// the state holds a single aggregator. The aggregators are sorted by address and the first one
+	// that does not collect data from all regions is adapted, so that the gap is not hidden by another one
+	var aggregator *config.ConfigurationAggregrator
+	for _, res := range g.FindResourcesByType("aws_config_configuration_aggregator") {
+		adapted := adaptConfigurationAggregator(res)
+		if aggregator == nil || (aggregator.SourceAllRegions.IsTrue() && !adapted.SourceAllRegions.IsTrue()) {
+			aggregator = &adapted
+		}
+	}
+
+	if aggregator == nil {
+		return config.Config{}
+	}
+	return config.Config{
+		ConfigurationAggregrator: *aggregator,
+	}
+}
+
+func adaptConfigurationAggregator(res *Node) config.ConfigurationAggregrator {
+	aggregator := config.ConfigurationAggregrator{
+		Metadata:         res.Metadata(),
+		SourceAllRegions: types.BoolDefault(false, res.Metadata()),
+	}
+
+	for _, source := range []string{"account_aggregation_source", "organization_aggregation_source"} {
+		if allRegions := res.GetAttr(source).GetNestedAttr("all_regions").AsBool(); allRegions != nil {
+			aggregator.SourceAllRegions = types.Bool(*allRegions, res.Metadata())
+			break
+		}
+	}
+
+	return aggregator
+}
@@ -0,0 +1,133 @@
+package tfplanadapt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/cloudwatch"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/ec2"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/elasticache"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/elasticsearch"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/kms"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/redshift"
+	"github.com/aquasecurity/defsec/pkg/state"
+	"github.com/aquasecurity/defsec/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptDataStores(t *testing.T) {
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			EC2: ec2.EC2{
+				VPCs:    []ec2.VPC{{}},
+				Subnets: []ec2.Subnet{{}},
+			},
+			KMS: kms.KMS{
+				Keys: []kms.Key{
+					{
+						Usage:           types.String("ENCRYPT_DECRYPT", types.Metadata{}),
+						RotationEnabled: types.Bool(true, types.Metadata{}),
+					},
+				},
+			},
+			CloudWatch: cloudwatch.CloudWatch{
+				LogGroups: []cloudwatch.LogGroup{
+					{
+						Arn:  types.StringUnresolvable(types.Metadata{}),
+						Name: types.String("opensearch-audit", types.Metadata{}),
+					},
+				},
+			},
+			ElastiCache: elasticache.ElastiCache{
+				Clusters: []elasticache.Cluster{
+					{
+						Engine:   types.String("memcached", types.Metadata{}),
+						NodeType: types.String("cache.t3.micro", types.Metadata{}),
+					},
+					{
+						Engine:                 types.String("redis", types.Metadata{}),
+						NodeType:               types.String("cache.m5.large", types.Metadata{}),
+						SnapshotRetentionLimit: types.Int(5, types.Metadata{}),
+					},
+				},
+				ReplicationGroups: []elasticache.ReplicationGroup{
+					{
+						TransitEncryptionEnabled: types.Bool(true, types.Metadata{}),
+						AtRestEncryptionEnabled:  types.Bool(true, types.Metadata{}),
+					},
+				},
+			},
+			Redshift: redshift.Redshift{
+				Clusters: []redshift.Cluster{
+					{
+						ClusterIdentifier:                types.String("warehouse", types.Metadata{}),
+						NodeType:                         types.String("ra3.xlplus", types.Metadata{}),
+						VpcId:                            types.StringUnresolvable(types.Metadata{}),
+						NumberOfNodes:                    types.Int(2, types.Metadata{}),
+						AllowVersionUpgrade:              types.Bool(true, types.Metadata{}),
+						MasterUsername:                   types.String("admin", types.Metadata{}),
+						AutomatedSnapshotRetentionPeriod: types.Int(7, types.Metadata{}),
+						LoggingEnabled:                   types.Bool(true, types.Metadata{}),
+						EndPoint: redshift.EndPoint{
+							Port: types.Int(5439, types.Metadata{}),
+						},
+						Encryption: redshift.Encryption{
+							Enabled:  types.Bool(true, types.Metadata{}),
+							KMSKeyID: types.StringUnresolvable(types.Metadata{}),
+						},
+						SubnetGroupName: types.String("main", types.Metadata{}),
+					},
+				},
+				ClusterParameters: []redshift.ClusterParameter{
+					{
+						ParameterName:  types.String("require_ssl", types.Metadata{}),
+						ParameterValue: types.String("true", types.Metadata{}),
+					},
+				},
+			},
+			Elasticsearch: elasticsearch.Elasticsearch{
+				Domains: []elasticsearch.Domain{
+					{
+						DomainName:     types.String("legacy", types.Metadata{}),
+						AccessPolicies: types.String(`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"es:*"}]}`, types.Metadata{}),
+						Endpoint: elasticsearch.Endpoint{
+							TLSPolicy: types.String("Policy-Min-TLS-1-0-2019-07", types.Metadata{}),
+						},
+					},
+					{
+						DomainName:             types.String("logs", types.Metadata{}),
+						AccessPolicies:         types.String(`{"Version":"2012-10-17","Statement":[]}`, types.Metadata{}),
+						DedicatedMasterEnabled: types.Bool(true, types.Metadata{}),
+						VpcId:                  types.StringUnresolvable(types.Metadata{}),
+						LogPublishing: elasticsearch.LogPublishing{
+							AuditEnabled:          types.Bool(true, types.Metadata{}),
+							CloudWatchLogGroupArn: types.StringUnresolvable(types.Metadata{}),
+						},
+						TransitEncryption: elasticsearch.TransitEncryption{
+							Enabled: types.Bool(true, types.Metadata{}),
+						},
+						AtRestEncryption: elasticsearch.AtRestEncryption{
+							Enabled:  types.Bool(true, types.Metadata{}),
+							KmsKeyId: types.StringUnresolvable(types.Metadata{}),
+						},
+						Endpoint: elasticsearch.Endpoint{
+							EnforceHTTPS: types.Bool(true, types.Metadata{}),
+							TLSPolicy:    types.String("Policy-Min-TLS-1-2-2019-07", types.Metadata{}),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got := runAdaptTest(t, filepath.Join("testdata", "datastores", "tfplan.json"), expected)
+
+	// the VPC and the key are created by the plan
+	domain := got.AWS.Elasticsearch.Domains[1]
+	assert.False(t, domain.VpcId.GetMetadata().IsResolvable())
+	assert.False(t, domain.AtRestEncryption.KmsKeyId.GetMetadata().IsResolvable())
+	assert.False(t, got.AWS.Redshift.Clusters[0].VpcId.GetMetadata().IsResolvable())
+}
@@ -0,0 +1,68 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/documentdb"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptDocumentDB(g *Graph) documentdb.DocumentDB {
+	adoptedInstances := make(map[string]struct{})
+
+	var clusters []documentdb.Cluster
+	for _, res := range g.FindResourcesByType("aws_docdb_cluster") {
+		cluster := documentdb.Cluster{
+			Metadata:              res.Metadata(),
+			Identifier:            res.GetStringAttr("cluster_identifier"),
+			BackupRetentionPeriod: res.GetIntAttr("backup_retention_period", 1),
+			StorageEncrypted:      res.GetBoolAttr("storage_encrypted"),
+			KMSKeyID:              getKMSKeyID(g, res, "kms_key_id"),
+		}
+
+		for _, logExport := range res.GetAttr("enabled_cloudwatch_logs_exports").AsStrings() {
+			cluster.EnabledLogExports = append(cluster.EnabledLogExports, types.String(logExport, res.Metadata()))
+		}
+
+		for _, instanceRes := range res.FindAllBackRelatedOrMatching(
+			g, "aws_docdb_cluster_instance", "cluster_identifier", "id", "cluster_identifier",
+		) {
+			// the storage of the instances is encrypted with the key of the cluster
+			cluster.Instances = append(cluster.Instances, documentdb.Instance{
+				Metadata: instanceRes.Metadata(),
+				KMSKeyID: cluster.KMSKeyID,
+			})
+			adoptedInstances[instanceRes.ID()] = struct{}{}
+		}
+
+		clusters = append(clusters, cluster)
+	}
+
+	// instances of clusters that are not managed by the plan
+	for _, instanceRes := range g.FindResourcesByType("aws_docdb_cluster_instance") {
+		if _, adopted := adoptedInstances[instanceRes.ID()]; adopted {
+			continue
+		}
+
+		instance := documentdb.Instance{
+			Metadata: instanceRes.Metadata(),
+			KMSKeyID: instanceRes.GetStringAttr("kms_key_id"),
+		}
+		if instance.KMSKeyID.IsEmpty() {
+			instance.KMSKeyID = types.StringUnresolvable(instanceRes.Metadata())
+		}
+
+		// only the instance is managed by the plan, the settings of the cluster are not known
+		metadata := types.NewUnmanagedMetadata()
+		clusters = append(clusters, documentdb.Cluster{
+			Metadata:              metadata,
+			Identifier:            instanceRes.GetStringAttr("cluster_identifier"),
+			BackupRetentionPeriod: types.IntUnresolvable(metadata),
+			StorageEncrypted:      types.BoolUnresolvable(metadata),
+			KMSKeyID:              types.StringUnresolvable(metadata),
+			Instances:             []documentdb.Instance{instance},
+		})
+	}
+
+	return documentdb.DocumentDB{
+		Clusters: clusters,
+	}
+}
@@ -0,0 +1,67 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/dynamodb"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptDynamoDB(g *Graph) dynamodb.DynamoDB {
+	return dynamodb.DynamoDB{
+		DAXClusters: adaptDAXClusters(g),
+		Tables:      adaptTables(g),
+	}
+}
+
+func adaptDAXClusters(g *Graph) []dynamodb.DAXCluster {
+	var clusters []dynamodb.DAXCluster
+	for _, res := range g.FindResourcesByType("aws_dax_cluster") {
+		clusters = append(clusters, dynamodb.DAXCluster{
+			Metadata: res.Metadata(),
+			ServerSideEncryption: dynamodb.ServerSideEncryption{
+				Metadata: res.Metadata(),
+				Enabled:  res.GetAttr("server_side_encryption").GetBoolAttr("enabled"),
+				KMSKeyID: types.StringDefault("", res.Metadata()),
+			},
+			PointInTimeRecovery: types.BoolDefault(false, res.Metadata()),
+		})
+	}
+	return clusters
+}
+
+func adaptTables(g *Graph) []dynamodb.Table {
+	var tables []dynamodb.Table
+	for _, res := range g.FindResourcesByType("aws_dynamodb_table") {
+		tables = append(tables, dynamodb.Table{
+			Metadata:             res.Metadata(),
+			ServerSideEncryption: adaptTableEncryption(g, res),
+			PointInTimeRecovery:  res.GetAttr("point_in_time_recovery").GetBoolAttr("enabled"),
+		})
+	}
+	return tables
+}
+
+func adaptTableEncryption(g *Graph, res *Node) dynamodb.ServerSideEncryption {
+	sse := res.GetAttr("server_side_encryption")
+
+	encryption := dynamodb.ServerSideEncryption{
+		Metadata: res.Metadata(),
+		Enabled:  sse.GetBoolAttr("enabled"),
+		KMSKeyID: sse.GetStringAttr("kms_key_arn"),
+	}
+
+	if encryption.Enabled.IsFalse() {
+		return encryption
+	}
+
+	encryption.KMSKeyID = resolveKMSKeyAttr(g, res, encryption.KMSKeyID, "server_side_encryption.kms_key_arn")
+	if encryption.KMSKeyID.IsEmpty() {
+		if !res.hasReference("server_side_encryption.kms_key_arn") {
+			// the AWS managed key is used if the key is not specified
+			encryption.KMSKeyID = types.StringDefault(dynamodb.DefaultKMSKeyID, res.Metadata())
+		} else {
+			encryption.KMSKeyID = types.StringUnresolvable(res.Metadata())
+		}
+	}
+
+	return encryption
+}
@@ -0,0 +1,109 @@
+package tfplanadapt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/dynamodb"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/iam"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/kms"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/sns"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/sqs"
+	"github.com/aquasecurity/defsec/pkg/state"
+	"github.com/aquasecurity/defsec/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptDynamoDBAndMessaging(t *testing.T) {
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			KMS: kms.KMS{
+				Keys: []kms.Key{
+					{
+						Usage:           types.String("ENCRYPT_DECRYPT", types.Metadata{}),
+						RotationEnabled: types.Bool(true, types.Metadata{}),
+					},
+				},
+			},
+			DynamoDB: dynamodb.DynamoDB{
+				DAXClusters: []dynamodb.DAXCluster{
+					{
+						ServerSideEncryption: dynamodb.ServerSideEncryption{
+							Enabled: types.Bool(true, types.Metadata{}),
+						},
+					},
+				},
+				Tables: []dynamodb.Table{
+					{},
+					{
+						ServerSideEncryption: dynamodb.ServerSideEncryption{
+							Enabled:  types.Bool(true, types.Metadata{}),
+							KMSKeyID: types.StringUnresolvable(types.Metadata{}),
+						},
+						PointInTimeRecovery: types.Bool(true, types.Metadata{}),
+					},
+					{
+						ServerSideEncryption: dynamodb.ServerSideEncryption{
+							Enabled:  types.Bool(true, types.Metadata{}),
+							KMSKeyID: types.String(dynamodb.DefaultKMSKeyID, types.Metadata{}),
+						},
+					},
+				},
+			},
+			SNS: sns.SNS{
+				Topics: []sns.Topic{
+					{
+						Encryption: sns.Encryption{
+							KMSKeyID: types.String("alias/aws/sns", types.Metadata{}),
+						},
+					},
+					{
+						Encryption: sns.Encryption{
+							KMSKeyID: types.StringUnresolvable(types.Metadata{}),
+						},
+					},
+				},
+			},
+			SQS: sqs.SQS{
+				Queues: []sqs.Queue{
+					{
+						Encryption: sqs.Encryption{
+							ManagedEncryption: types.Bool(true, types.Metadata{}),
+						},
+					},
+					{
+						Encryption: sqs.Encryption{
+							KMSKeyID: types.StringUnresolvable(types.Metadata{}),
+						},
+						Policies: []iam.Policy{
+							{
+								Document: iam.Document{
+									Parsed: mustParsePolicy(t, `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"sqs:SendMessage","Resource":"arn:aws:sqs:us-east-1:111111111111:jobs","Principal":{"Service":"sns.amazonaws.com"}}]}`),
+								},
+							},
+						},
+					},
+					{
+						Policies: []iam.Policy{
+							{
+								Document: iam.Document{
+									Parsed: mustParsePolicy(t, `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"sqs:*","Resource":"*"}]}`),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got := runAdaptTest(t, filepath.Join("testdata", "dynamodb", "tfplan.json"), expected)
+
+	// the queue is encrypted with the key created by the plan
+	queue := got.AWS.SQS.Queues[1]
+	assert.False(t, queue.Encryption.KMSKeyID.IsEmpty())
+	assert.Equal(t, "aws_sqs_queue_policy.jobs", queue.Policies[0].Metadata.Reference())
+}
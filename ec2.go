@@ -1,13 +1,22 @@
 package tfplanadapt
 
 import (
+	"encoding/base64"
+
 	"github.com/aquasecurity/defsec/pkg/providers/aws/ec2"
 	"github.com/aquasecurity/defsec/pkg/types"
 )
 
 func adaptEC2(g *Graph) ec2.EC2 {
 	return ec2.EC2{
-		Instances: adaptInstances(g),
+		Instances:            adaptInstances(g),
+		LaunchConfigurations: adaptLaunchConfigurations(g),
+		LaunchTemplates:      adaptLaunchTemplates(g),
+		Volumes:              adaptVolumes(g),
+		VPCs:                 adaptVPCs(g),
+		SecurityGroups:       adaptSecurityGroups(g),
+		NetworkACLs:          adaptNetworkACLs(g),
+		Subnets:              adaptSubnets(g),
 	}
 }
 
@@ -15,33 +24,39 @@ func adaptInstances(g *Graph) []ec2.Instance {
 	var instances []ec2.Instance
 	for _, res := range g.FindResourcesByType("aws_instance") {
 		instance := ec2.Instance{
-			UserData:        res.GetStringAttr("user_data"),
+			Metadata:        res.Metadata(),
+			UserData:        getUserData(res),
 			MetadataOptions: getMetadataOptions(res),
 		}
 
-		if launchTemplate := findRelatedLaunchTemplate(res); launchTemplate != nil {
-			instance = launchTemplate.Instance
-		}
-
-		instance.RootBlockDevice = &ec2.BlockDevice{
-			Encrypted: res.GetAttr("root_block_device").GetBoolAttr("encrypted"),
+		// the settings of the instance override the settings of the launch template
+		launchTemplateRes := findRelatedLaunchTemplate(res)
+		if launchTemplateRes != nil {
+			launchTemplate := adaptLaunchTemplate(launchTemplateRes)
+			if instance.UserData.IsEmpty() {
+				instance.UserData = launchTemplate.UserData
+			}
+			if res.GetNestedAttr("metadata_options.http_tokens").IsNil() {
+				instance.MetadataOptions.HttpTokens = launchTemplate.MetadataOptions.HttpTokens
+			}
+			if res.GetNestedAttr("metadata_options.http_endpoint").IsNil() {
+				instance.MetadataOptions.HttpEndpoint = launchTemplate.MetadataOptions.HttpEndpoint
+			}
 		}
 
-		for _, blockDevice := range res.GetAttr("ebs_block_device").ToList() {
-			instance.EBSBlockDevices = append(instance.EBSBlockDevices, &ec2.BlockDevice{
-				Encrypted: blockDevice.GetBoolAttr("encrypted"),
-			})
+		for _, attrName := range []string{"vpc_security_group_ids", "security_groups"} {
+			for _, group := range res.FindAllRelated("aws_security_group", attrName, "id", "name") {
+				instance.SecurityGroups = append(instance.SecurityGroups, adaptNestedSecurityGroup(group))
+			}
 		}
 
-		for _, res := range g.FindResourcesByType("aws_ebs_encryption_by_default") {
-			if res.GetBoolAttr("enabled").IsFalse() {
-				continue
-			}
+		instance.RootBlockDevice, instance.EBSBlockDevices = adaptInstanceBlockDevices(res, launchTemplateRes)
 
-			instance.RootBlockDevice.Encrypted = types.BoolDefault(true, types.Metadata{})
+		if isEBSEncryptedByDefault(g, res) {
+			instance.RootBlockDevice.Encrypted = types.BoolDefault(true, res.Metadata())
 			for i := 0; i < len(instance.EBSBlockDevices); i++ {
 				ebs := instance.EBSBlockDevices[i]
-				ebs.Encrypted = types.BoolDefault(true, types.Metadata{})
+				ebs.Encrypted = types.BoolDefault(true, res.Metadata())
 			}
 		}
 
@@ -51,30 +66,256 @@ func adaptInstances(g *Graph) []ec2.Instance {
 	return instances
 }
 
-func findRelatedLaunchTemplate(n *Node) *ec2.LaunchTemplate {
-	if launchTemplate := n.FindRelated("aws_launch_template", "launch_template.id", "id"); launchTemplate != nil {
-		return adaptLaunchTemplate(launchTemplate)
+// isEBSEncryptedByDefault reports whether the EBS encryption by default is enabled
+// in the region and account of the resource
+func isEBSEncryptedByDefault(g *Graph, res *Node) bool {
+	for _, encryptionByDefault := range g.FindResourcesByType("aws_ebs_encryption_by_default") {
+		if encryptionByDefault.sameRegion(res) && encryptionByDefault.GetBoolAttr("enabled").IsTrue() {
+			return true
+		}
+	}
+	return false
+}
+
+func adaptVolumes(g *Graph) []ec2.Volume {
+	var volumes []ec2.Volume
+	for _, res := range g.FindResourcesByType("aws_ebs_volume") {
+		encrypted := res.GetBoolAttr("encrypted")
+		if isEBSEncryptedByDefault(g, res) {
+			encrypted = types.BoolDefault(true, res.Metadata())
+		}
+
+		volumes = append(volumes, ec2.Volume{
+			Metadata: res.Metadata(),
+			Encryption: ec2.Encryption{
+				Metadata: res.Metadata(),
+				Enabled:  encrypted,
+				KMSKeyID: getKMSKeyID(g, res, "kms_key_id"),
+			},
+		})
+	}
+	return volumes
+}
+
+func adaptLaunchConfigurations(g *Graph) []ec2.LaunchConfiguration {
+	var launchConfigurations []ec2.LaunchConfiguration
+	for _, res := range g.FindResourcesByType("aws_launch_configuration") {
+		launchConfiguration := ec2.LaunchConfiguration{
+			Metadata:          res.Metadata(),
+			Name:              res.GetStringAttr("name"),
+			AssociatePublicIP: res.GetBoolAttr("associate_public_ip_address"),
+			RootBlockDevice: &ec2.BlockDevice{
+				Metadata:  res.Metadata(),
+				Encrypted: res.GetAttr("root_block_device").GetBoolAttr("encrypted"),
+			},
+			MetadataOptions: getMetadataOptions(res),
+			UserData:        getUserData(res),
+		}
+
+		for _, blockDevice := range res.GetAttr("ebs_block_device").ToList() {
+			launchConfiguration.EBSBlockDevices = append(launchConfiguration.EBSBlockDevices, &ec2.BlockDevice{
+				Metadata:  res.Metadata(),
+				Encrypted: blockDevice.GetBoolAttr("encrypted"),
+			})
+		}
+
+		launchConfigurations = append(launchConfigurations, launchConfiguration)
+	}
+	return launchConfigurations
+}
+
+func adaptLaunchTemplates(g *Graph) []ec2.LaunchTemplate {
+	autoscalingGroups := make(map[string]*Node)
+	for _, asg := range g.FindResourcesByType("aws_autoscaling_group") {
+		for _, launchTemplate := range findAutoscalingGroupLaunchTemplates(asg) {
+			if _, exists := autoscalingGroups[launchTemplate.ID()]; !exists {
+				autoscalingGroups[launchTemplate.ID()] = asg
+			}
+		}
 	}
 
-	if launchTemplate := n.FindRelated("aws_launch_template", "launch_template.name", "name"); launchTemplate != nil {
-		return adaptLaunchTemplate(launchTemplate)
+	var launchTemplates []ec2.LaunchTemplate
+	for _, res := range g.FindResourcesByType("aws_launch_template") {
+		launchTemplate := adaptLaunchTemplate(res)
+		// the instances launched by the autoscaling group are checked through the template,
+		// so the template is marked as used by the group
+		if asg, exists := autoscalingGroups[res.ID()]; exists {
+			launchTemplate.Metadata = launchTemplate.Metadata.WithParent(asg.Metadata())
+			launchTemplate.Instance.Metadata = launchTemplate.Instance.Metadata.WithParent(asg.Metadata())
+		}
+		launchTemplates = append(launchTemplates, *launchTemplate)
+	}
+	return launchTemplates
+}
+
+var launchTemplateReferences = []struct {
+	attr   string
+	toAttr string
+}{
+	{"launch_template.id", "id"},
+	{"launch_template.name", "name"},
+}
+
+// autoscalingGroupLaunchTemplateReferences are the references of the autoscaling group to the templates
+// it launches instances from, the overrides of the mixed instances policy may refer to other templates
+var autoscalingGroupLaunchTemplateReferences = append([]struct {
+	attr   string
+	toAttr string
+}{
+	{"mixed_instances_policy.launch_template.launch_template_specification.launch_template_id", "id"},
+	{"mixed_instances_policy.launch_template.launch_template_specification.launch_template_name", "name"},
+	{"mixed_instances_policy.launch_template.override.launch_template_specification.launch_template_id", "id"},
+	{"mixed_instances_policy.launch_template.override.launch_template_specification.launch_template_name", "name"},
+}, launchTemplateReferences...)
+
+func findAutoscalingGroupLaunchTemplates(asg *Node) []*Node {
+	var launchTemplates []*Node
+	for _, ref := range autoscalingGroupLaunchTemplateReferences {
+		for _, launchTemplate := range asg.FindAllRelated("aws_launch_template", ref.attr, ref.toAttr) {
+			if !containsNode(launchTemplates, launchTemplate) {
+				launchTemplates = append(launchTemplates, launchTemplate)
+			}
+		}
 	}
+	return launchTemplates
+}
 
+func findRelatedLaunchTemplate(n *Node) *Node {
+	for _, ref := range launchTemplateReferences {
+		if launchTemplate := n.FindRelated("aws_launch_template", ref.attr, ref.toAttr); launchTemplate != nil {
+			return launchTemplate
+		}
+	}
 	return nil
 }
 
 func adaptLaunchTemplate(n *Node) *ec2.LaunchTemplate {
-	return &ec2.LaunchTemplate{
+	launchTemplate := &ec2.LaunchTemplate{
+		Metadata: n.Metadata(),
+		Name:     n.GetStringAttr("name"),
 		Instance: ec2.Instance{
+			Metadata:        n.Metadata(),
 			MetadataOptions: getMetadataOptions(n),
-			UserData:        n.GetStringAttr("user_data"),
+			UserData:        getLaunchTemplateUserData(n),
 		},
 	}
+
+	for _, mapping := range adaptLaunchTemplateBlockDevices(n) {
+		launchTemplate.EBSBlockDevices = append(launchTemplate.EBSBlockDevices, mapping.device)
+	}
+
+	return launchTemplate
+}
+
+type blockDeviceMapping struct {
+	deviceName string
+	device     *ec2.BlockDevice
+}
+
+func adaptLaunchTemplateBlockDevices(n *Node) []blockDeviceMapping {
+	var mappings []blockDeviceMapping
+	for _, mapping := range n.GetAttr("block_device_mappings").ToList() {
+		ebs := mapping.GetNestedAttr("ebs")
+		if len(ebs.ToList()) == 0 {
+			continue
+		}
+		// the launch template stores the encryption flag as a string
+		mappings = append(mappings, blockDeviceMapping{
+			deviceName: mapping.GetStringAttr("device_name").Value(),
+			device: &ec2.BlockDevice{
+				Metadata:  n.Metadata(),
+				Encrypted: types.Bool(ebs.GetStringAttr("encrypted").EqualTo("true"), n.Metadata()),
+			},
+		})
+	}
+	return mappings
+}
+
+// adaptInstanceBlockDevices adapts the block devices of the instance merged with the block device
+// mappings of the launch template. The encryption of the instance device overrides the encryption of
+// the template device with the same name, the template devices not defined by the instance are also attached.
+// The root device depends on the AMI, so its encryption is unresolvable if the plan does not contain it
+func adaptInstanceBlockDevices(res, launchTemplate *Node) (*ec2.BlockDevice, []*ec2.BlockDevice) {
+	var mappings []blockDeviceMapping
+	if launchTemplate != nil {
+		mappings = adaptLaunchTemplateBlockDevices(launchTemplate)
+	}
+
+	overridden := make(map[string]struct{})
+	findMapping := func(deviceName string) *ec2.BlockDevice {
+		for _, mapping := range mappings {
+			if mapping.deviceName == deviceName {
+				overridden[deviceName] = struct{}{}
+				return mapping.device
+			}
+		}
+		return nil
+	}
+
+	root := &ec2.BlockDevice{
+		Metadata:  res.Metadata(),
+		Encrypted: types.BoolUnresolvable(res.Metadata()),
+	}
+
+	if rootBlockDevice := res.GetAttr("root_block_device"); len(rootBlockDevice.ToList()) > 0 {
+		if encrypted := rootBlockDevice.GetNestedAttr("encrypted"); !encrypted.IsNil() {
+			root.Encrypted = rootBlockDevice.GetBoolAttr("encrypted")
+		} else if deviceName := rootBlockDevice.GetStringAttr("device_name"); deviceName.IsNotEmpty() {
+			if mapping := findMapping(deviceName.Value()); mapping != nil {
+				root.Encrypted = mapping.Encrypted
+			}
+		}
+	}
+
+	var ebsBlockDevices []*ec2.BlockDevice
+	for _, blockDevice := range res.GetAttr("ebs_block_device").ToList() {
+		device := &ec2.BlockDevice{
+			Metadata:  res.Metadata(),
+			Encrypted: blockDevice.GetBoolAttr("encrypted"),
+		}
+		mapping := findMapping(blockDevice.GetStringAttr("device_name").Value())
+		if mapping != nil && blockDevice.GetNestedAttr("encrypted").IsNil() {
+			device.Encrypted = mapping.Encrypted
+		}
+		ebsBlockDevices = append(ebsBlockDevices, device)
+	}
+
+	for _, mapping := range mappings {
+		if _, exists := overridden[mapping.deviceName]; !exists {
+			ebsBlockDevices = append(ebsBlockDevices, mapping.device)
+		}
+	}
+
+	return root, ebsBlockDevices
 }
 
 func getMetadataOptions(n *Node) ec2.MetadataOptions {
 	return ec2.MetadataOptions{
+		Metadata:     n.Metadata(),
 		HttpTokens:   n.GetAttr("metadata_options").GetStringAttr("http_tokens"),
 		HttpEndpoint: n.GetAttr("metadata_options").GetStringAttr("http_endpoint"),
 	}
 }
+
+// getUserData returns the user data of the resource, the base64 encoded user data is decoded
+func getUserData(n *Node) types.StringValue {
+	if encoded := n.GetStringAttr("user_data_base64"); encoded.IsNotEmpty() {
+		if decoded, err := base64.StdEncoding.DecodeString(encoded.Value()); err == nil {
+			return types.String(string(decoded), n.Metadata())
+		}
+	}
+	return n.GetStringAttr("user_data")
+}
+
+// getLaunchTemplateUserData returns the decoded user data, the launch template stores it base64 encoded
+func getLaunchTemplateUserData(n *Node) types.StringValue {
+	userData := n.GetStringAttr("user_data")
+	if userData.IsEmpty() {
+		return userData
+	}
+
+	if decoded, err := base64.StdEncoding.DecodeString(userData.Value()); err == nil {
+		return types.String(string(decoded), n.Metadata())
+	}
+	return userData
+}
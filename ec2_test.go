@@ -6,14 +6,18 @@ import (
 
 	"github.com/aquasecurity/defsec/pkg/providers/aws"
 	"github.com/aquasecurity/defsec/pkg/providers/aws/ec2"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/kms"
 	"github.com/aquasecurity/defsec/pkg/state"
 	"github.com/aquasecurity/defsec/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAdaptEC2(t *testing.T) {
 
 	expected := &state.State{
 		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
 			EC2: ec2.EC2{
 				Instances: []ec2.Instance{
 					{
@@ -27,9 +31,297 @@ func TestAdaptEC2(t *testing.T) {
 						},
 					},
 				},
+				LaunchTemplates: []ec2.LaunchTemplate{
+					{
+						Name: types.String("test_launch_template", types.Metadata{}),
+						Instance: ec2.Instance{
+							MetadataOptions: ec2.MetadataOptions{
+								HttpTokens:   types.String("required", types.Metadata{}),
+								HttpEndpoint: types.String("enabled", types.Metadata{}),
+							},
+							UserData: types.String("some data", types.Metadata{}),
+						},
+					},
+				},
 			},
 		},
 	}
 
 	runAdaptTest(t, filepath.Join("testdata", "ec2", "tfplan.json"), expected)
 }
+
+func TestAdaptEC2Networking(t *testing.T) {
+
+	webGroup := ec2.SecurityGroup{
+		IsDefault:   types.Bool(false, types.Metadata{}),
+		Description: types.String("Web", types.Metadata{}),
+		IngressRules: []ec2.SecurityGroupRule{
+			{
+				Description: types.String("HTTPS", types.Metadata{}),
+				CIDRs: []types.StringValue{
+					types.String("0.0.0.0/0", types.Metadata{}),
+				},
+			},
+			{
+				Description: types.String("SSH", types.Metadata{}),
+				CIDRs: []types.StringValue{
+					types.String("10.0.0.0/8", types.Metadata{}),
+				},
+			},
+		},
+		EgressRules: []ec2.SecurityGroupRule{
+			{
+				Description: types.String("All", types.Metadata{}),
+				CIDRs: []types.StringValue{
+					types.String("0.0.0.0/0", types.Metadata{}),
+				},
+			},
+		},
+	}
+
+	defaultGroup := ec2.SecurityGroup{
+		IsDefault: types.Bool(true, types.Metadata{}),
+	}
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			EC2: ec2.EC2{
+				VPCs: []ec2.VPC{
+					{
+						IsDefault:       types.Bool(false, types.Metadata{}),
+						FlowLogsEnabled: types.Bool(true, types.Metadata{}),
+						SecurityGroups:  []ec2.SecurityGroup{webGroup, defaultGroup},
+					},
+					{
+						IsDefault: types.Bool(true, types.Metadata{}),
+					},
+				},
+				SecurityGroups: []ec2.SecurityGroup{
+					webGroup,
+					defaultGroup,
+					{
+						IngressRules: []ec2.SecurityGroupRule{
+							{
+								Description: types.String("External", types.Metadata{}),
+								CIDRs: []types.StringValue{
+									types.String("0.0.0.0/0", types.Metadata{}),
+								},
+							},
+						},
+					},
+				},
+				NetworkACLs: []ec2.NetworkACL{
+					{
+						IsDefaultRule: types.Bool(false, types.Metadata{}),
+						Rules: []ec2.NetworkACLRule{
+							{
+								Type:     types.String("ingress", types.Metadata{}),
+								Action:   types.String("allow", types.Metadata{}),
+								Protocol: types.String("tcp", types.Metadata{}),
+								CIDRs: []types.StringValue{
+									types.String("0.0.0.0/0", types.Metadata{}),
+								},
+							},
+							{
+								Type:     types.String("ingress", types.Metadata{}),
+								Action:   types.String("deny", types.Metadata{}),
+								Protocol: types.String("-1", types.Metadata{}),
+								CIDRs: []types.StringValue{
+									types.String("10.0.0.0/8", types.Metadata{}),
+								},
+							},
+						},
+					},
+				},
+				Subnets: []ec2.Subnet{
+					{
+						MapPublicIpOnLaunch: types.Bool(true, types.Metadata{}),
+					},
+				},
+			},
+		},
+	}
+
+	got := runAdaptTest(t, filepath.Join("testdata", "ec2_vpc", "tfplan.json"), expected)
+
+	// the group of the external rule is not managed by the plan, only the rule is
+	external := got.AWS.EC2.SecurityGroups[2]
+	assert.True(t, external.Metadata.IsUnmanaged())
+	assert.Equal(t, "aws_vpc_security_group_ingress_rule.external", external.IngressRules[0].Metadata.Reference())
+	// the groups nested in the VPC are copies of the adapted groups
+	for _, group := range got.AWS.EC2.VPCs[0].SecurityGroups {
+		assert.True(t, group.Metadata.IsUnmanaged())
+	}
+
+	reported := make(map[string]int)
+	for _, result := range Scan(got) {
+		if result.Rule().AVDID == "AVD-AWS-0099" {
+			assert.Equal(t, "aws_default_security_group.default", result.Metadata().Reference())
+		}
+		reported[result.Rule().AVDID+" "+result.Metadata().Reference()]++
+	}
+	for key, count := range reported {
+		assert.Equal(t, 1, count, key)
+	}
+}
+
+func TestAdaptEC2Autoscaling(t *testing.T) {
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			EC2: ec2.EC2{
+				LaunchTemplates: []ec2.LaunchTemplate{
+					{
+						Name: types.String("batch", types.Metadata{}),
+						Instance: ec2.Instance{
+							MetadataOptions: ec2.MetadataOptions{
+								HttpTokens:   types.String("required", types.Metadata{}),
+								HttpEndpoint: types.String("enabled", types.Metadata{}),
+							},
+							UserData: types.String("", types.Metadata{}),
+						},
+					},
+					{
+						Name: types.String("web", types.Metadata{}),
+						Instance: ec2.Instance{
+							MetadataOptions: ec2.MetadataOptions{
+								HttpTokens:   types.String("optional", types.Metadata{}),
+								HttpEndpoint: types.String("enabled", types.Metadata{}),
+							},
+							UserData: types.String("#!/bin/bash\necho hello", types.Metadata{}),
+							EBSBlockDevices: []*ec2.BlockDevice{
+								{
+									Encrypted: types.Bool(false, types.Metadata{}),
+								},
+							},
+						},
+					},
+				},
+				LaunchConfigurations: []ec2.LaunchConfiguration{
+					{
+						Name:              types.String("legacy", types.Metadata{}),
+						AssociatePublicIP: types.Bool(true, types.Metadata{}),
+						RootBlockDevice: &ec2.BlockDevice{
+							Encrypted: types.Bool(true, types.Metadata{}),
+						},
+						EBSBlockDevices: []*ec2.BlockDevice{
+							{
+								Encrypted: types.Bool(false, types.Metadata{}),
+							},
+						},
+						MetadataOptions: ec2.MetadataOptions{
+							HttpTokens:   types.String("required", types.Metadata{}),
+							HttpEndpoint: types.String("enabled", types.Metadata{}),
+						},
+					},
+				},
+				Volumes: []ec2.Volume{
+					{
+						Encryption: ec2.Encryption{
+							Enabled:  types.Bool(true, types.Metadata{}),
+							KMSKeyID: types.StringUnresolvable(types.Metadata{}),
+						},
+					},
+				},
+			},
+			KMS: kms.KMS{
+				Keys: []kms.Key{
+					{
+						Usage:           types.String("ENCRYPT_DECRYPT", types.Metadata{}),
+						RotationEnabled: types.Bool(true, types.Metadata{}),
+					},
+				},
+			},
+		},
+	}
+
+	got := runAdaptTest(t, filepath.Join("testdata", "ec2_autoscaling", "tfplan.json"), expected)
+
+	// the templates are marked as used by the autoscaling groups that launch instances from them,
+	// directly or through the mixed instances policy
+	batch, web := got.AWS.EC2.LaunchTemplates[0], got.AWS.EC2.LaunchTemplates[1]
+	require.NotNil(t, batch.Metadata.Parent())
+	assert.Equal(t, "aws_launch_template.batch", batch.Metadata.Reference())
+	assert.Equal(t, "aws_autoscaling_group.batch", batch.Metadata.Parent().Reference())
+	require.NotNil(t, web.Metadata.Parent())
+	assert.Equal(t, "aws_autoscaling_group.web", web.Metadata.Parent().Reference())
+	assert.Equal(t, "aws_autoscaling_group.web", web.Instance.Metadata.Parent().Reference())
+
+	// the instances launched by the autoscaling group are checked through the launch template
+	var templateFindings int
+	for _, result := range Scan(got) {
+		assert.NotEqual(t, "aws_autoscaling_group.web", result.Metadata().Reference(), result.Rule().AVDID)
+		if result.Metadata().Reference() == "aws_launch_template.web" {
+			templateFindings++
+		}
+	}
+	assert.NotZero(t, templateFindings)
+}
+
+func TestAdaptEC2LaunchTemplateMerge(t *testing.T) {
+
+	launchTemplate := ec2.Instance{
+		MetadataOptions: ec2.MetadataOptions{
+			HttpTokens:   types.String("required", types.Metadata{}),
+			HttpEndpoint: types.String("enabled", types.Metadata{}),
+		},
+		UserData: types.String("template data", types.Metadata{}),
+		EBSBlockDevices: []*ec2.BlockDevice{
+			{Encrypted: types.Bool(true, types.Metadata{})},
+			{Encrypted: types.Bool(false, types.Metadata{})},
+			{Encrypted: types.Bool(true, types.Metadata{})},
+		},
+	}
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			EC2: ec2.EC2{
+				Instances: []ec2.Instance{
+					{
+						MetadataOptions: ec2.MetadataOptions{
+							HttpTokens:   types.String("optional", types.Metadata{}),
+							HttpEndpoint: types.String("enabled", types.Metadata{}),
+						},
+						UserData: types.String("template data", types.Metadata{}),
+						// the root device name is not known until apply
+						RootBlockDevice: &ec2.BlockDevice{
+							Encrypted: types.BoolUnresolvable(types.Metadata{}),
+						},
+						EBSBlockDevices: []*ec2.BlockDevice{
+							{Encrypted: types.Bool(true, types.Metadata{})},
+							{Encrypted: types.Bool(false, types.Metadata{})},
+							{Encrypted: types.Bool(true, types.Metadata{})},
+							{Encrypted: types.Bool(true, types.Metadata{})},
+						},
+					},
+					{
+						MetadataOptions: ec2.MetadataOptions{
+							HttpTokens:   types.String("required", types.Metadata{}),
+							HttpEndpoint: types.String("enabled", types.Metadata{}),
+						},
+						UserData: types.String("instance data", types.Metadata{}),
+						RootBlockDevice: &ec2.BlockDevice{
+							Encrypted: types.BoolUnresolvable(types.Metadata{}),
+						},
+						EBSBlockDevices: launchTemplate.EBSBlockDevices,
+					},
+				},
+				LaunchTemplates: []ec2.LaunchTemplate{
+					{
+						Name:     types.String("base", types.Metadata{}),
+						Instance: launchTemplate,
+					},
+				},
+			},
+		},
+	}
+
+	got := runAdaptTest(t, filepath.Join("testdata", "ec2_launch_template", "tfplan.json"), expected)
+
+	for _, instance := range got.AWS.EC2.Instances {
+		assert.False(t, instance.RootBlockDevice.Encrypted.GetMetadata().IsResolvable())
+	}
+}
@@ -0,0 +1,61 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/ecr"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/iam"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptECR(g *Graph) ecr.ECR {
+	var repositories []ecr.Repository
+	for _, res := range g.FindResourcesByType("aws_ecr_repository") {
+		repository := ecr.Repository{
+			Metadata: res.Metadata(),
+			ImageScanning: ecr.ImageScanning{
+				Metadata:   res.Metadata(),
+				ScanOnPush: res.GetAttr("image_scanning_configuration").GetBoolAttr("scan_on_push"),
+			},
+			ImageTagsImmutable: types.Bool(
+				res.GetStringAttr("image_tag_mutability").EqualTo("IMMUTABLE"), res.Metadata(),
+			),
+			Encryption: adaptRepositoryEncryption(g, res),
+		}
+
+		for _, policy := range res.FindAllBackRelatedOrMatching(
+			g, "aws_ecr_repository_policy", "repository", "name", "id",
+		) {
+			if document, ok := adaptPolicyDocument(policy, "policy"); ok {
+				repository.Policies = append(repository.Policies, iam.Policy{
+					Metadata: policy.Metadata(),
+					Name:     types.StringDefault("", policy.Metadata()),
+					Document: document,
+					Builtin:  types.BoolDefault(false, policy.Metadata()),
+				})
+			}
+		}
+
+		repositories = append(repositories, repository)
+	}
+
+	return ecr.ECR{
+		Repositories: repositories,
+	}
+}
+
+func adaptRepositoryEncryption(g *Graph, res *Node) ecr.Encryption {
+	encryptionConfig := res.GetAttr("encryption_configuration")
+
+	encryption := ecr.Encryption{
+		Metadata: res.Metadata(),
+		Type:     types.StringDefault(ecr.EncryptionTypeAES256, res.Metadata()),
+		KMSKeyID: resolveKMSKeyAttr(
+			g, res, encryptionConfig.GetStringAttr("kms_key"), "encryption_configuration.kms_key",
+		),
+	}
+
+	if encryptionType := encryptionConfig.GetStringAttr("encryption_type"); encryptionType.IsNotEmpty() {
+		encryption.Type = encryptionType
+	}
+
+	return encryption
+}
@@ -0,0 +1,72 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/ecs"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptECS(g *Graph) ecs.ECS {
+	return ecs.ECS{
+		Clusters:        adaptECSClusters(g),
+		TaskDefinitions: adaptTaskDefinitions(g),
+	}
+}
+
+func adaptECSClusters(g *Graph) []ecs.Cluster {
+	var clusters []ecs.Cluster
+	for _, res := range g.FindResourcesByType("aws_ecs_cluster") {
+		cluster := ecs.Cluster{
+			Metadata: res.Metadata(),
+			Settings: ecs.ClusterSettings{
+				Metadata:                 res.Metadata(),
+				ContainerInsightsEnabled: types.BoolDefault(false, res.Metadata()),
+			},
+		}
+
+		for _, setting := range res.GetAttr("setting").ToList() {
+			if !setting.GetStringAttr("name").EqualTo("containerInsights") {
+				continue
+			}
+			// "enhanced" is the container insights with enhanced observability
+			value := setting.GetStringAttr("value")
+			cluster.Settings.ContainerInsightsEnabled = types.Bool(
+				value.EqualTo("enabled") || value.EqualTo("enhanced"), setting.Metadata(),
+			)
+		}
+
+		clusters = append(clusters, cluster)
+	}
+	return clusters
+}
+
+func adaptTaskDefinitions(g *Graph) []ecs.TaskDefinition {
+	var taskDefinitions []ecs.TaskDefinition
+	for _, res := range g.FindResourcesByType("aws_ecs_task_definition") {
+		taskDefinition := ecs.TaskDefinition{
+			Metadata: res.Metadata(),
+		}
+
+		// the definitions are not known if they are rendered from the values of other resources
+		if raw := res.GetAttr("container_definitions").AsString(); raw != nil && *raw != "" {
+			if definitions, err := ecs.CreateDefinitionsFromString(res.Metadata(), *raw); err == nil {
+				taskDefinition.ContainerDefinitions = definitions
+			}
+		}
+
+		for _, volume := range res.GetAttr("volume").ToList() {
+			efsConfig := volume.GetNestedAttr("efs_volume_configuration")
+			taskDefinition.Volumes = append(taskDefinition.Volumes, ecs.Volume{
+				Metadata: volume.Metadata(),
+				EFSVolumeConfiguration: ecs.EFSVolumeConfiguration{
+					Metadata: efsConfig.Metadata(),
+					TransitEncryptionEnabled: types.Bool(
+						efsConfig.GetStringAttr("transit_encryption").EqualTo("ENABLED"), efsConfig.Metadata(),
+					),
+				},
+			})
+		}
+
+		taskDefinitions = append(taskDefinitions, taskDefinition)
+	}
+	return taskDefinitions
+}
@@ -0,0 +1,115 @@
+package tfplanadapt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/ecr"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/ecs"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/iam"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/kms"
+	"github.com/aquasecurity/defsec/pkg/state"
+	"github.com/aquasecurity/defsec/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptECS(t *testing.T) {
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			KMS: kms.KMS{
+				Keys: []kms.Key{
+					{
+						Usage: types.String("ENCRYPT_DECRYPT", types.Metadata{}),
+					},
+				},
+			},
+			ECS: ecs.ECS{
+				Clusters: []ecs.Cluster{
+					{},
+					{
+						Settings: ecs.ClusterSettings{
+							ContainerInsightsEnabled: types.Bool(true, types.Metadata{}),
+						},
+					},
+				},
+				TaskDefinitions: []ecs.TaskDefinition{
+					{
+						Volumes: []ecs.Volume{
+							{
+								EFSVolumeConfiguration: ecs.EFSVolumeConfiguration{
+									TransitEncryptionEnabled: types.Bool(true, types.Metadata{}),
+								},
+							},
+							{},
+						},
+						ContainerDefinitions: []ecs.ContainerDefinition{
+							{
+								Name:      types.String("app", types.Metadata{}),
+								Image:     types.String("app:latest", types.Metadata{}),
+								CPU:       types.Int(256, types.Metadata{}),
+								Memory:    types.Int(512, types.Metadata{}),
+								Essential: types.Bool(true, types.Metadata{}),
+								PortMappings: []ecs.PortMapping{
+									{
+										ContainerPort: types.Int(80, types.Metadata{}),
+										HostPort:      types.Int(80, types.Metadata{}),
+									},
+								},
+								Environment: []ecs.EnvVar{
+									{
+										Name:  "DB_PASSWORD",
+										Value: "secret",
+									},
+								},
+								Privileged: types.Bool(true, types.Metadata{}),
+							},
+						},
+					},
+				},
+			},
+			ECR: ecr.ECR{
+				Repositories: []ecr.Repository{
+					{
+						ImageScanning: ecr.ImageScanning{
+							ScanOnPush: types.Bool(true, types.Metadata{}),
+						},
+						ImageTagsImmutable: types.Bool(true, types.Metadata{}),
+						Policies: []iam.Policy{
+							{
+								Document: iam.Document{
+									Parsed: mustParsePolicy(t, `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":"*","Action":"ecr:GetDownloadUrlForLayer"}]}`),
+								},
+							},
+						},
+						Encryption: ecr.Encryption{
+							Type:     types.String(ecr.EncryptionTypeKMS, types.Metadata{}),
+							KMSKeyID: types.StringUnresolvable(types.Metadata{}),
+						},
+					},
+					{
+						Policies: []iam.Policy{
+							{
+								Document: iam.Document{
+									Parsed: mustParsePolicy(t, `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::111111111111:root"},"Action":"ecr:BatchGetImage"}]}`),
+								},
+							},
+						},
+						Encryption: ecr.Encryption{
+							Type: types.String(ecr.EncryptionTypeAES256, types.Metadata{}),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got := runAdaptTest(t, filepath.Join("testdata", "ecs", "tfplan.json"), expected)
+
+	// the repository is encrypted with the key created by the plan
+	kmsKeyID := got.AWS.ECR.Repositories[0].Encryption.KMSKeyID
+	assert.False(t, kmsKeyID.GetMetadata().IsResolvable())
+	assert.False(t, kmsKeyID.IsEmpty())
+}
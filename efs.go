@@ -0,0 +1,19 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/efs"
+)
+
+func adaptEFS(g *Graph) efs.EFS {
+	var fileSystems []efs.FileSystem
+	for _, res := range g.FindResourcesByType("aws_efs_file_system") {
+		fileSystems = append(fileSystems, efs.FileSystem{
+			Metadata:  res.Metadata(),
+			Encrypted: res.GetBoolAttr("encrypted"),
+		})
+	}
+
+	return efs.EFS{
+		FileSystems: fileSystems,
+	}
+}
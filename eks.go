@@ -0,0 +1,96 @@
+package tfplanadapt
+
+import (
+	"slices"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws/eks"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptEKS(g *Graph) eks.EKS {
+	var clusters []eks.Cluster
+	for _, res := range g.FindResourcesByType("aws_eks_cluster") {
+		clusters = append(clusters, eks.Cluster{
+			Metadata:            res.Metadata(),
+			Logging:             adaptClusterLogging(res),
+			Encryption:          adaptClusterEncryption(g, res),
+			PublicAccessEnabled: adaptClusterPublicAccess(res),
+			PublicAccessCIDRs:   adaptClusterPublicAccessCIDRs(res),
+		})
+	}
+
+	return eks.EKS{
+		Clusters: clusters,
+	}
+}
+
+func adaptClusterLogging(res *Node) eks.Logging {
+	logTypes := res.GetAttr("enabled_cluster_log_types").AsStrings()
+	enabled := func(logType string) types.BoolValue {
+		return types.Bool(slices.Contains(logTypes, logType), res.Metadata())
+	}
+
+	return eks.Logging{
+		Metadata:          res.Metadata(),
+		API:               enabled("api"),
+		Audit:             enabled("audit"),
+		Authenticator:     enabled("authenticator"),
+		ControllerManager: enabled("controllerManager"),
+		Scheduler:         enabled("scheduler"),
+	}
+}
+
+func adaptClusterEncryption(g *Graph, res *Node) eks.Encryption {
+	encryption := eks.Encryption{
+		Metadata: res.Metadata(),
+		Secrets:  types.BoolDefault(false, res.Metadata()),
+		KMSKeyID: types.StringDefault("", res.Metadata()),
+	}
+
+	encryptionConfig := res.GetAttr("encryption_config")
+	if encryptionConfig.IsNil() {
+		return encryption
+	}
+
+	encryption.Secrets = types.Bool(
+		slices.Contains(encryptionConfig.GetNestedAttr("resources").AsStrings(), "secrets"), res.Metadata(),
+	)
+
+	encryption.KMSKeyID = resolveKMSKeyAttr(
+		g, res, encryptionConfig.GetStringAttr("provider.key_arn"), "encryption_config.provider.key_arn",
+	)
+	if encryption.KMSKeyID.IsEmpty() && len(encryptionConfig.ToList()) > 0 {
+		// the key is required by the encryption config, so it is not known until apply.
+		// The plan does not contain the expressions of dynamic blocks, so the reference to the key
+		// cannot be resolved if the block is dynamic, e.g. the encryption config of the community module
+		encryption.KMSKeyID = types.StringUnresolvable(res.Metadata())
+	}
+
+	return encryption
+}
+
+// adaptClusterPublicAccess returns whether the public endpoint is enabled, it is enabled by default
+func adaptClusterPublicAccess(res *Node) types.BoolValue {
+	if publicAccess := res.GetAttr("vpc_config").GetNestedAttr("endpoint_public_access").AsBool(); publicAccess != nil {
+		return types.Bool(*publicAccess, res.Metadata())
+	}
+	return types.BoolDefault(true, res.Metadata())
+}
+
+// adaptClusterPublicAccessCIDRs returns the CIDR blocks that can access the public endpoint,
+// AWS allows access from any address if they are not set
+func adaptClusterPublicAccessCIDRs(res *Node) []types.StringValue {
+	cidrsAttr := res.GetAttr("vpc_config").GetNestedAttr("public_access_cidrs")
+	if cidrsAttr.IsNil() {
+		if res.hasReference("vpc_config.public_access_cidrs") {
+			return nil
+		}
+		return []types.StringValue{types.StringDefault("0.0.0.0/0", res.Metadata())}
+	}
+
+	var cidrs []types.StringValue
+	for _, cidr := range cidrsAttr.AsStrings() {
+		cidrs = append(cidrs, types.String(cidr, res.Metadata()))
+	}
+	return cidrs
+}
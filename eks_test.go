@@ -0,0 +1,95 @@
+package tfplanadapt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/eks"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/kms"
+	"github.com/aquasecurity/defsec/pkg/state"
+	"github.com/aquasecurity/defsec/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdaptEKS(t *testing.T) {
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			KMS: kms.KMS{
+				Keys: []kms.Key{
+					{
+						Usage:           types.String("ENCRYPT_DECRYPT", types.Metadata{}),
+						RotationEnabled: types.Bool(true, types.Metadata{}),
+					},
+					{
+						Usage:           types.String("ENCRYPT_DECRYPT", types.Metadata{}),
+						RotationEnabled: types.Bool(true, types.Metadata{}),
+					},
+				},
+			},
+			EKS: eks.EKS{
+				Clusters: []eks.Cluster{
+					{
+						Logging: eks.Logging{
+							API:           types.Bool(true, types.Metadata{}),
+							Audit:         types.Bool(true, types.Metadata{}),
+							Authenticator: types.Bool(true, types.Metadata{}),
+						},
+						Encryption: eks.Encryption{
+							Secrets:  types.Bool(true, types.Metadata{}),
+							KMSKeyID: types.StringUnresolvable(types.Metadata{}),
+						},
+						PublicAccessEnabled: types.Bool(true, types.Metadata{}),
+						PublicAccessCIDRs: []types.StringValue{
+							types.String("10.0.0.0/8", types.Metadata{}),
+						},
+					},
+					{
+						Logging: eks.Logging{
+							API: types.Bool(true, types.Metadata{}),
+						},
+						Encryption: eks.Encryption{
+							Secrets:  types.Bool(true, types.Metadata{}),
+							KMSKeyID: types.StringUnresolvable(types.Metadata{}),
+						},
+						PublicAccessEnabled: types.Bool(true, types.Metadata{}),
+						PublicAccessCIDRs: []types.StringValue{
+							types.String("0.0.0.0/0", types.Metadata{}),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got := runAdaptTest(t, filepath.Join("testdata", "eks", "tfplan.json"), expected)
+
+	for _, cluster := range got.AWS.EKS.Clusters {
+		assert.False(t, cluster.Encryption.KMSKeyID.IsEmpty())
+	}
+}
+
+func TestNestedModuleReferences(t *testing.T) {
+	g := readPlanGraph(t, filepath.Join("testdata", "eks", "tfplan.json"))
+
+	cluster := g.GetResource(`module.legacy["dev"].aws_eks_cluster.this`)
+	require.NotNil(t, cluster)
+
+	// the key is passed through the module variable from the output of another module
+	// with the same name as the module nested in module.eks
+	key, _ := findKMSKey(g, cluster, "encryption_config.provider.key_arn")
+	require.NotNil(t, key)
+	assert.Equal(t, "module.kms.aws_kms_key.this[0]", key.Address)
+
+	// the key of the module nested in module.eks is not confused with the key of module.kms
+	nestedKey := g.GetResource("module.eks.module.kms.aws_kms_key.this[0]")
+	require.NotNil(t, nestedKey)
+	for _, edge := range cluster.neighbors {
+		assert.NotSame(t, nestedKey, edge.to)
+	}
+	require.NotNil(t, nestedKey.provider)
+	assert.Equal(t, "aws", nestedKey.provider.key)
+}
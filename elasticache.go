@@ -0,0 +1,66 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/elasticache"
+)
+
+func adaptElastiCache(g *Graph) elasticache.ElastiCache {
+	return elasticache.ElastiCache{
+		Clusters:          adaptElastiCacheClusters(g),
+		ReplicationGroups: adaptReplicationGroups(g),
+		SecurityGroups:    adaptElastiCacheSecurityGroups(g),
+	}
+}
+
+func adaptElastiCacheClusters(g *Graph) []elasticache.Cluster {
+	var clusters []elasticache.Cluster
+	for _, res := range g.FindResourcesByType("aws_elasticache_cluster") {
+		cluster := elasticache.Cluster{
+			Metadata:               res.Metadata(),
+			Engine:                 res.GetStringAttr("engine"),
+			NodeType:               res.GetStringAttr("node_type"),
+			SnapshotRetentionLimit: res.GetIntAttr("snapshot_retention_limit"),
+		}
+
+		// the cluster that belongs to the replication group inherits its settings
+		if group := res.FindRelated(
+			"aws_elasticache_replication_group", "replication_group_id", "id", "replication_group_id",
+		); group != nil {
+			if cluster.Engine.IsEmpty() {
+				cluster.Engine = group.GetStringAttr("engine", "redis")
+			}
+			if cluster.NodeType.IsEmpty() {
+				cluster.NodeType = group.GetStringAttr("node_type")
+			}
+			if res.GetAttr("snapshot_retention_limit").IsNil() {
+				cluster.SnapshotRetentionLimit = group.GetIntAttr("snapshot_retention_limit")
+			}
+		}
+
+		clusters = append(clusters, cluster)
+	}
+	return clusters
+}
+
+func adaptReplicationGroups(g *Graph) []elasticache.ReplicationGroup {
+	var groups []elasticache.ReplicationGroup
+	for _, res := range g.FindResourcesByType("aws_elasticache_replication_group") {
+		groups = append(groups, elasticache.ReplicationGroup{
+			Metadata:                 res.Metadata(),
+			TransitEncryptionEnabled: res.GetBoolAttr("transit_encryption_enabled"),
+			AtRestEncryptionEnabled:  res.GetBoolAttr("at_rest_encryption_enabled"),
+		})
+	}
+	return groups
+}
+
+func adaptElastiCacheSecurityGroups(g *Graph) []elasticache.SecurityGroup {
+	var groups []elasticache.SecurityGroup
+	for _, res := range g.FindResourcesByType("aws_elasticache_security_group") {
+		groups = append(groups, elasticache.SecurityGroup{
+			Metadata:    res.Metadata(),
+			Description: res.GetStringAttr("description"),
+		})
+	}
+	return groups
+}
@@ -0,0 +1,132 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/elasticsearch"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+// domainTypes maps the domain resource types to the types of their policy resources
+var domainTypes = map[string]string{
+	"aws_elasticsearch_domain": "aws_elasticsearch_domain_policy",
+	"aws_opensearch_domain":    "aws_opensearch_domain_policy",
+}
+
+func adaptElasticsearch(g *Graph) elasticsearch.Elasticsearch {
+	var domains []elasticsearch.Domain
+	for _, domainType := range []string{"aws_elasticsearch_domain", "aws_opensearch_domain"} {
+		for _, res := range g.FindResourcesByType(domainType) {
+			domains = append(domains, adaptDomain(g, res))
+		}
+	}
+
+	return elasticsearch.Elasticsearch{
+		Domains: domains,
+	}
+}
+
+func adaptDomain(g *Graph, res *Node) elasticsearch.Domain {
+	endpointOptions := res.GetAttr("domain_endpoint_options")
+	encryptAtRest := res.GetAttr("encrypt_at_rest")
+
+	domain := elasticsearch.Domain{
+		Metadata:               res.Metadata(),
+		DomainName:             res.GetStringAttr("domain_name"),
+		AccessPolicies:         res.GetStringAttr("access_policies"),
+		DedicatedMasterEnabled: res.GetAttr("cluster_config").GetBoolAttr("dedicated_master_enabled"),
+		VpcId:                  adaptDomainVpcID(res),
+		LogPublishing:          adaptDomainLogPublishing(res),
+		TransitEncryption: elasticsearch.TransitEncryption{
+			Metadata: res.Metadata(),
+			Enabled:  res.GetAttr("node_to_node_encryption").GetBoolAttr("enabled"),
+		},
+		AtRestEncryption: elasticsearch.AtRestEncryption{
+			Metadata: res.Metadata(),
+			Enabled:  encryptAtRest.GetBoolAttr("enabled"),
+			KmsKeyId: resolveKMSKeyAttr(g, res, encryptAtRest.GetStringAttr("kms_key_id"), "encrypt_at_rest.kms_key_id"),
+		},
+		ServiceSoftwareOptions: elasticsearch.ServiceSoftwareOptions{
+			Metadata:        res.Metadata(),
+			CurrentVersion:  types.StringDefault("", res.Metadata()),
+			NewVersion:      types.StringDefault("", res.Metadata()),
+			UpdateAvailable: types.BoolDefault(false, res.Metadata()),
+			UpdateStatus:    types.StringDefault("", res.Metadata()),
+		},
+		Endpoint: elasticsearch.Endpoint{
+			Metadata:     res.Metadata(),
+			EnforceHTTPS: endpointOptions.GetBoolAttr("enforce_https"),
+			TLSPolicy:    endpointOptions.GetStringAttr("tls_security_policy"),
+		},
+	}
+
+	// the policy attribute of the domain is ignored if the domain has a separate policy resource
+	if policy := findDomainPolicy(g, res); policy != nil {
+		domain.AccessPolicies = policy.GetStringAttr("access_policies")
+	}
+
+	return domain
+}
+
+// findDomainPolicy returns the policy that refers to the domain or contains its name
+func findDomainPolicy(g *Graph, domain *Node) *Node {
+	policyType := domainTypes[domain.resourceType]
+	if policy := domain.FindBackRelated(policyType, "domain_name", "domain_name"); policy != nil {
+		return policy
+	}
+
+	if name := domain.GetStringAttr("domain_name"); name.IsNotEmpty() {
+		for _, policy := range g.FindResourcesByType(policyType) {
+			if policy.GetStringAttr("domain_name").EqualTo(name.Value()) {
+				return policy
+			}
+		}
+	}
+	return nil
+}
+
+// adaptDomainVpcID returns the VPC of the domain. The VPC is computed from the subnets,
+// so it is resolved from the subnet if the domain is created by the plan
+func adaptDomainVpcID(res *Node) types.StringValue {
+	vpcOptions := res.GetAttr("vpc_options")
+	if vpcOptions.IsNil() || len(vpcOptions.ToList()) == 0 {
+		return types.StringDefault("", res.Metadata())
+	}
+
+	if vpcID := vpcOptions.GetStringAttr("vpc_id"); vpcID.IsNotEmpty() {
+		return vpcID
+	}
+
+	if subnet := res.FindRelated("aws_subnet", "vpc_options.subnet_ids", "id"); subnet != nil {
+		return adaptSubnetVpcID(subnet, res.Metadata())
+	}
+
+	return types.StringUnresolvable(res.Metadata())
+}
+
+func adaptDomainLogPublishing(res *Node) elasticsearch.LogPublishing {
+	logPublishing := elasticsearch.LogPublishing{
+		Metadata:              res.Metadata(),
+		AuditEnabled:          types.BoolDefault(false, res.Metadata()),
+		CloudWatchLogGroupArn: types.StringDefault("", res.Metadata()),
+	}
+
+	for _, option := range res.GetAttr("log_publishing_options").ToList() {
+		if !option.GetStringAttr("log_type").EqualTo("AUDIT_LOGS") {
+			continue
+		}
+
+		// the option is enabled by default
+		enabled := option.GetNestedAttr("enabled").AsBool()
+		logPublishing.AuditEnabled = types.Bool(enabled == nil || *enabled, res.Metadata())
+
+		logPublishing.CloudWatchLogGroupArn = option.GetStringAttr("cloudwatch_log_group_arn")
+		if logPublishing.CloudWatchLogGroupArn.IsEmpty() {
+			if logGroup := res.FindRelated(
+				"aws_cloudwatch_log_group", "log_publishing_options.cloudwatch_log_group_arn", "arn",
+			); logGroup != nil {
+				logPublishing.CloudWatchLogGroupArn = getLogGroupArn(logGroup)
+			}
+		}
+	}
+
+	return logPublishing
+}
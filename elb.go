@@ -0,0 +1,116 @@
+package tfplanadapt
+
+import (
+	"strings"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws/elb"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+var (
+	loadBalancerTypes = []string{"aws_lb", "aws_alb"}
+	listenerTypes     = []string{"aws_lb_listener", "aws_alb_listener"}
+)
+
+func adaptELB(g *Graph) elb.ELB {
+	adoptedListeners := make(map[string]struct{})
+
+	var loadBalancers []elb.LoadBalancer
+	for _, lbType := range loadBalancerTypes {
+		for _, res := range g.FindResourcesByType(lbType) {
+			loadBalancer := adaptLoadBalancer(res)
+			for _, listenerType := range listenerTypes {
+				for _, listener := range res.FindAllBackRelatedOrMatching(g, listenerType, "load_balancer_arn", "arn", "id") {
+					loadBalancer.Listeners = append(loadBalancer.Listeners, adaptListener(listener))
+					adoptedListeners[listener.ID()] = struct{}{}
+				}
+			}
+			loadBalancers = append(loadBalancers, loadBalancer)
+		}
+	}
+
+	// listeners of load balancers that are not managed by the plan
+	for _, listenerType := range listenerTypes {
+		for _, listener := range g.FindResourcesByType(listenerType) {
+			if _, adopted := adoptedListeners[listener.ID()]; adopted {
+				continue
+			}
+
+			// only the listener is managed by the plan
+			metadata := types.NewUnmanagedMetadata()
+			loadBalancer := elb.LoadBalancer{
+				Metadata:                metadata,
+				Type:                    types.StringDefault("", metadata),
+				DropInvalidHeaderFields: types.BoolUnresolvable(metadata),
+				Internal:                types.BoolUnresolvable(metadata),
+			}
+
+			// the settings of the load balancer may be read by the data source
+			for _, lbType := range loadBalancerTypes {
+				if lb := listener.FindRelated(lbType, "load_balancer_arn", "arn", "id"); lb != nil {
+					settings := adaptLoadBalancer(lb)
+					loadBalancer.Type = types.String(settings.Type.Value(), metadata)
+					loadBalancer.DropInvalidHeaderFields = types.Bool(settings.DropInvalidHeaderFields.Value(), metadata)
+					loadBalancer.Internal = types.Bool(settings.Internal.Value(), metadata)
+					break
+				}
+			}
+
+			loadBalancer.Listeners = []elb.Listener{adaptListener(listener)}
+			loadBalancers = append(loadBalancers, loadBalancer)
+		}
+	}
+
+	for _, res := range g.FindResourcesByType("aws_elb") {
+		loadBalancers = append(loadBalancers, adaptClassicLoadBalancer(res))
+	}
+
+	return elb.ELB{
+		LoadBalancers: loadBalancers,
+	}
+}
+
+func adaptLoadBalancer(res *Node) elb.LoadBalancer {
+	return elb.LoadBalancer{
+		Metadata:                res.Metadata(),
+		Type:                    res.GetStringAttr("load_balancer_type", elb.TypeApplication),
+		DropInvalidHeaderFields: res.GetBoolAttr("drop_invalid_header_fields"),
+		Internal:                res.GetBoolAttr("internal"),
+	}
+}
+
+func adaptListener(res *Node) elb.Listener {
+	listener := elb.Listener{
+		Metadata:  res.Metadata(),
+		Protocol:  res.GetStringAttr("protocol"),
+		TLSPolicy: res.GetStringAttr("ssl_policy"),
+	}
+
+	for _, action := range res.GetAttr("default_action").ToList() {
+		listener.DefaultActions = append(listener.DefaultActions, elb.Action{
+			Metadata: action.Metadata(),
+			Type:     action.GetStringAttr("type"),
+		})
+	}
+
+	return listener
+}
+
+func adaptClassicLoadBalancer(res *Node) elb.LoadBalancer {
+	loadBalancer := elb.LoadBalancer{
+		Metadata:                res.Metadata(),
+		Type:                    types.String(elb.TypeClassic, res.Metadata()),
+		DropInvalidHeaderFields: types.BoolDefault(false, res.Metadata()),
+		Internal:                res.GetBoolAttr("internal"),
+	}
+
+	for _, listener := range res.GetAttr("listener").ToList() {
+		loadBalancer.Listeners = append(loadBalancer.Listeners, elb.Listener{
+			Metadata:  listener.Metadata(),
+			Protocol:  types.String(strings.ToUpper(listener.GetStringAttr("lb_protocol").Value()), listener.Metadata()),
+			TLSPolicy: types.StringDefault("", listener.Metadata()),
+		})
+	}
+
+	return loadBalancer
+}
@@ -0,0 +1,151 @@
+package tfplanadapt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/cloudfront"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/elb"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/s3"
+	"github.com/aquasecurity/defsec/pkg/state"
+	"github.com/aquasecurity/defsec/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptLoadBalancersAndCloudfront(t *testing.T) {
+
+	forward := []elb.Action{
+		{
+			Type: types.String("forward", types.Metadata{}),
+		},
+	}
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			S3: s3.S3{
+				Buckets: []s3.Bucket{
+					{
+						Name: types.String("cdn-logs", types.Metadata{}),
+					},
+				},
+			},
+			ELB: elb.ELB{
+				LoadBalancers: []elb.LoadBalancer{
+					{
+						Type:     types.String(elb.TypeNetwork, types.Metadata{}),
+						Internal: types.Bool(true, types.Metadata{}),
+						Listeners: []elb.Listener{
+							{
+								Protocol:       types.String("TCP", types.Metadata{}),
+								DefaultActions: forward,
+							},
+						},
+					},
+					{
+						Type:                    types.String(elb.TypeApplication, types.Metadata{}),
+						DropInvalidHeaderFields: types.Bool(true, types.Metadata{}),
+						Listeners: []elb.Listener{
+							{
+								Protocol: types.String("HTTP", types.Metadata{}),
+								DefaultActions: []elb.Action{
+									{
+										Type: types.String("redirect", types.Metadata{}),
+									},
+								},
+							},
+							{
+								Protocol:       types.String("HTTPS", types.Metadata{}),
+								TLSPolicy:      types.String("ELBSecurityPolicy-TLS13-1-2-2021-06", types.Metadata{}),
+								DefaultActions: forward,
+							},
+						},
+					},
+					{
+						Listeners: []elb.Listener{
+							{
+								Protocol:       types.String("HTTP", types.Metadata{}),
+								DefaultActions: forward,
+							},
+						},
+					},
+					{
+						Type: types.String(elb.TypeApplication, types.Metadata{}),
+						Listeners: []elb.Listener{
+							{
+								Protocol:       types.String("HTTPS", types.Metadata{}),
+								TLSPolicy:      types.String("ELBSecurityPolicy-2016-08", types.Metadata{}),
+								DefaultActions: forward,
+							},
+						},
+					},
+					{
+						Type: types.String(elb.TypeClassic, types.Metadata{}),
+						Listeners: []elb.Listener{
+							{
+								Protocol: types.String("HTTP", types.Metadata{}),
+							},
+						},
+					},
+				},
+			},
+			Cloudfront: cloudfront.Cloudfront{
+				Distributions: []cloudfront.Distribution{
+					{
+						WAFID: types.StringUnresolvable(types.Metadata{}),
+						Logging: cloudfront.Logging{
+							Bucket: types.StringUnresolvable(types.Metadata{}),
+						},
+						DefaultCacheBehaviour: cloudfront.CacheBehaviour{
+							ViewerProtocolPolicy: types.String(cloudfront.ViewerPolicyProtocolRedirectToHTTPS, types.Metadata{}),
+						},
+						OrdererCacheBehaviours: []cloudfront.CacheBehaviour{
+							{
+								ViewerProtocolPolicy: types.String(cloudfront.ViewerPolicyProtocolAllowAll, types.Metadata{}),
+							},
+						},
+						ViewerCertificate: cloudfront.ViewerCertificate{
+							SSLSupportMethod:       types.String("sni-only", types.Metadata{}),
+							MinimumProtocolVersion: types.String(cloudfront.ProtocolVersionTLS1_2, types.Metadata{}),
+						},
+					},
+					{
+						DefaultCacheBehaviour: cloudfront.CacheBehaviour{
+							ViewerProtocolPolicy: types.String(cloudfront.ViewerPolicyProtocolHTTPSOnly, types.Metadata{}),
+						},
+						ViewerCertificate: cloudfront.ViewerCertificate{
+							CloudfrontDefaultCertificate: types.Bool(true, types.Metadata{}),
+							MinimumProtocolVersion:       types.String("TLSv1", types.Metadata{}),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got := runAdaptTest(t, filepath.Join("testdata", "elb", "tfplan.json"), expected)
+
+	// the load balancers of the listeners are not managed by the plan, only the listeners are
+	external := got.AWS.ELB.LoadBalancers[2]
+	assert.True(t, external.Metadata.IsUnmanaged())
+	assert.Equal(t, "aws_lb_listener.external", external.Listeners[0].Metadata.Reference())
+	assert.False(t, external.Internal.IsFalse())
+
+	shared := got.AWS.ELB.LoadBalancers[3]
+	assert.True(t, shared.Metadata.IsUnmanaged())
+	assert.Equal(t, "aws_lb_listener.shared", shared.Listeners[0].Metadata.Reference())
+
+	for _, result := range Scan(got) {
+		switch result.Rule().AVDID {
+		case "AVD-AWS-0052", "AVD-AWS-0053":
+			assert.NotContains(t, []string{"aws_lb_listener.external", "aws_lb_listener.shared", "data.aws_lb.shared"},
+				result.Metadata().Reference())
+		}
+	}
+
+	// the WAF and the logging bucket are created by the plan
+	distribution := got.AWS.Cloudfront.Distributions[0]
+	assert.False(t, distribution.WAFID.IsEmpty())
+	assert.False(t, distribution.Logging.Bucket.IsEmpty())
+}
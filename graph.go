@@ -1,5 +1,7 @@
 package tfplanadapt
 
+import "sort"
+
 // Edge represents the link between resources
 type Edge struct {
 	from           *Node
@@ -9,7 +11,8 @@ type Edge struct {
 
 // Graph represents an oriented graph of resources
 type Graph struct {
-	nodes map[string]*Node
+	nodes     map[string]*Node
+	providers map[string]*providerConfig
 }
 
 // AddNode adds a node to the graph
@@ -33,15 +36,15 @@ func (g *Graph) AddEdge(from, to string, linkAttributes map[string]string) {
 	}
 }
 
-// AddEdgeFromResources adds an edge between resources based on module, type, and name
-func (g *Graph) AddEdgeFromResources(moduleName, fromType, fromName, toAddress string, linkAttributes map[string]string) {
+// AddEdgeFromResources adds edges from all instances of the resource to the resource with the given address
+func (g *Graph) AddEdgeFromResources(modulePath, fromType, fromName, toAddress string, linkAttributes map[string]string) {
 
 	toNode := g.nodes[toAddress]
 	if toNode == nil {
 		return
 	}
 
-	for _, fromNode := range g.FindResources(moduleName, fromType, fromName) {
+	for _, fromNode := range g.FindResources(modulePath, fromType, fromName) {
 		if fromNode == nil {
 			continue
 		}
@@ -56,21 +59,36 @@ func (g *Graph) AddEdgeFromResources(moduleName, fromType, fromName, toAddress s
 	}
 }
 
-// FindResourcesByType searches for resources by type
+// FindResourcesByType searches for managed resources by type, the resources are sorted by address.
+// Data sources only read the existing infrastructure, so they are not returned
 func (g *Graph) FindResourcesByType(resourceType string) []*Node {
+	return g.findNodesByType(resourceType, false)
+}
+
+// FindDataSourcesByType searches for data sources by type, the data sources are sorted by address
+func (g *Graph) FindDataSourcesByType(resourceType string) []*Node {
+	return g.findNodesByType(resourceType, true)
+}
+
+func (g *Graph) findNodesByType(resourceType string, dataSource bool) []*Node {
 	var result []*Node
 	for _, node := range g.nodes {
-		if node.resourceType == resourceType {
+		if node.resourceType == resourceType && node.isDataSource() == dataSource {
 			result = append(result, node)
 		}
 	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Address < result[j].Address
+	})
 	return result
 }
 
-func (g *Graph) FindResources(moduleName, resourceType, resourceName string) []*Node {
+// FindResources searches for the instances of the resource declared in the module.
+// The module path is the module address without instance keys, e.g. module.eks.module.kms
+func (g *Graph) FindResources(modulePath, resourceType, resourceName string) []*Node {
 	var result []*Node
 	for _, node := range g.nodes {
-		if node.moduleName == moduleName &&
+		if node.modulePath == modulePath &&
 			node.resourceType == resourceType &&
 			node.resourceName == resourceName {
 			result = append(result, node)
@@ -83,6 +101,51 @@ func (g *Graph) GetResource(address string) *Node {
 	return g.nodes[address]
 }
 
+// findResourceInstances returns the instances of the resource with the given address referenced by the resource.
+// If the address does not contain the instance keys of the modules, the instance of the module is taken from
+// the referencing resource, so each module instance refers to its own resources
+func (g *Graph) findResourceInstances(address string, from *Node) []*Node {
+	if node := g.nodes[address]; node != nil {
+		return []*Node{node}
+	}
+
+	trimmed := trimModuleKeys(address)
+
+	var result []*Node
+	for _, node := range g.nodes {
+		if trimModuleKeys(node.Address) == trimmed && sameModuleInstance(address, node.Address, from.Address) {
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+// sameModuleInstance reports whether the resource belongs to the module instances of the reference.
+// The module without the instance key in the reference is the instance of the referencing resource
+// if the referencing resource is declared in this module instance or its child modules
+func sameModuleInstance(reference, address, from string) bool {
+	refParts, parts, fromParts := splitAddress(reference), splitAddress(address), splitAddress(from)
+
+	shared := true
+	for i := 0; i+1 < len(parts) && parts[i] == "module"; i += 2 {
+		shared = shared && i+1 < len(fromParts) && fromParts[i] == "module" &&
+			trimInstanceKey(fromParts[i+1]) == trimInstanceKey(parts[i+1])
+
+		switch {
+		case refParts[i+1] != trimInstanceKey(refParts[i+1]):
+			if refParts[i+1] != parts[i+1] {
+				return false
+			}
+			shared = shared && fromParts[i+1] == parts[i+1]
+		case shared:
+			if fromParts[i+1] != parts[i+1] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 func NewGraph() *Graph {
 	return &Graph{
 		nodes: make(map[string]*Node),
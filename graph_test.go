@@ -1,9 +1,13 @@
 package tfplanadapt
 
 import (
+	"fmt"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGraph(t *testing.T) {
@@ -43,3 +47,123 @@ func TestGraph(t *testing.T) {
 	logBucket := loggingResource.FindRelated("aws_s3_bucket", "target_bucket", "id")
 	assert.NotNil(t, logBucket)
 }
+
+func TestFindAllBackRelatedOrMatching(t *testing.T) {
+	graph := NewGraph()
+
+	graph.AddNode(newNode("", "aws_sqs_queue.main", "aws_sqs_queue", "main", map[string]any{
+		"url": "https://sqs.us-east-1.amazonaws.com/111111111111/main",
+	}))
+	graph.AddNode(newNode("", "aws_sqs_queue_policy.linked", "aws_sqs_queue_policy", "linked", map[string]any{
+		"queue_url": nil,
+	}))
+	graph.AddNode(newNode("", "aws_sqs_queue_policy.literal", "aws_sqs_queue_policy", "literal", map[string]any{
+		"queue_url": "https://sqs.us-east-1.amazonaws.com/111111111111/main",
+	}))
+	graph.AddNode(newNode("", "aws_sqs_queue_policy.other", "aws_sqs_queue_policy", "other", map[string]any{
+		"queue_url": "https://sqs.us-east-1.amazonaws.com/111111111111/other",
+	}))
+	graph.AddNode(newNode("", "aws_sqs_queue_policy.empty", "aws_sqs_queue_policy", "empty", map[string]any{
+		"queue_url": "",
+	}))
+
+	graph.AddEdge("aws_sqs_queue_policy.linked", "aws_sqs_queue.main", map[string]string{
+		"queue_url": "url",
+	})
+
+	queue := graph.GetResource("aws_sqs_queue.main")
+	policies := queue.FindAllBackRelatedOrMatching(graph, "aws_sqs_queue_policy", "queue_url", "url", "id")
+
+	var addresses []string
+	for _, policy := range policies {
+		addresses = append(addresses, policy.Address)
+	}
+	assert.Equal(t, []string{"aws_sqs_queue_policy.linked", "aws_sqs_queue_policy.literal"}, addresses)
+}
+
+func TestDataSourcesAreNotAdapted(t *testing.T) {
+	g := readPlanGraph(t, filepath.Join("testdata", "data_sources", "tfplan.json"))
+
+	assert.Empty(t, g.FindResourcesByType("aws_s3_bucket"))
+	require.Len(t, g.FindDataSourcesByType("aws_s3_bucket"), 1)
+	assert.Equal(t, "data.aws_s3_bucket.shared", g.FindDataSourcesByType("aws_s3_bucket")[0].Address)
+
+	s := Adapt(g)
+	assert.Empty(t, s.AWS.S3.Buckets)
+	assert.Empty(t, s.AWS.EC2.VPCs)
+	assert.Len(t, s.AWS.EC2.Subnets, 1)
+
+	// the data sources only read the existing infrastructure, so the findings cannot be fixed by the plan
+	for _, result := range Scan(s) {
+		assert.False(t, strings.HasPrefix(result.Metadata().Reference(), "data."), result.Rule().AVDID)
+	}
+}
+
+func TestModuleInstanceReferences(t *testing.T) {
+	g := readPlanGraph(t, filepath.Join("testdata", "module_instances", "tfplan.json"))
+
+	// each instance of the module refers to the resources of the same instance
+	for _, key := range []string{"a", "b"} {
+		bucket := g.GetResource(fmt.Sprintf(`module.b[%q].aws_s3_bucket.this`, key))
+		require.NotNil(t, bucket)
+		require.Len(t, bucket.backLinks, 1)
+		assert.Equal(t, fmt.Sprintf(`module.b[%q].aws_s3_bucket_versioning.this`, key), bucket.backLinks[0].from.Address)
+	}
+
+	buckets := Adapt(g).AWS.S3.Buckets
+	require.Len(t, buckets, 2)
+	assert.Equal(t, "logs-a", buckets[0].Name.Value())
+	assert.True(t, buckets[0].Versioning.Enabled.IsTrue())
+	assert.Equal(t, "logs-b", buckets[1].Name.Value())
+	assert.True(t, buckets[1].Versioning.Enabled.IsFalse())
+}
+
+func TestSameModuleInstance(t *testing.T) {
+	tests := []struct {
+		name      string
+		reference string
+		address   string
+		from      string
+		expected  bool
+	}{
+		{
+			name:      "sibling in the same instance",
+			reference: "module.b.aws_s3_bucket.this",
+			address:   `module.b["a"].aws_s3_bucket.this`,
+			from:      `module.b["a"].aws_s3_bucket_versioning.this`,
+			expected:  true,
+		},
+		{
+			name:      "sibling in another instance",
+			reference: "module.b.aws_s3_bucket.this",
+			address:   `module.b["b"].aws_s3_bucket.this`,
+			from:      `module.b["a"].aws_s3_bucket_versioning.this`,
+		},
+		{
+			name:      "explicit instance key",
+			reference: `module.b["b"].aws_s3_bucket.this`,
+			address:   `module.b["b"].aws_s3_bucket.this`,
+			from:      "aws_s3_bucket_policy.this",
+			expected:  true,
+		},
+		{
+			name:      "child module of the same instance",
+			reference: "module.b.module.kms[0].aws_kms_key.this",
+			address:   `module.b["a"].module.kms[0].aws_kms_key.this`,
+			from:      `module.b["a"].aws_s3_bucket.this`,
+			expected:  true,
+		},
+		{
+			name:      "child module of another instance",
+			reference: "module.b.module.kms[0].aws_kms_key.this",
+			address:   `module.b["b"].module.kms[0].aws_kms_key.this`,
+			from:      `module.b["a"].aws_s3_bucket.this`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, sameModuleInstance(tt.reference, tt.address, tt.from))
+		})
+	}
+}
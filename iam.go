@@ -0,0 +1,257 @@
+package tfplanadapt
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws/iam"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptIAM(g *Graph) iam.IAM {
+	return iam.IAM{
+		PasswordPolicy: adaptPasswordPolicy(g),
+		Policies:       adaptPolicies(g),
+		Groups:         adaptGroups(g),
+		Users:          adaptUsers(g),
+		Roles:          adaptRoles(g),
+	}
+}
+
+func adaptPasswordPolicy(g *Graph) iam.PasswordPolicy {
+	passwordPolicies := g.FindResourcesByType("aws_iam_account_password_policy")
+	if len(passwordPolicies) == 0 {
+		return iam.PasswordPolicy{}
+	}
+
+	res := passwordPolicies[0]
+	return iam.PasswordPolicy{
+		Metadata:             res.Metadata(),
+		ReusePreventionCount: res.GetIntAttr("password_reuse_prevention"),
+		RequireLowercase:     res.GetBoolAttr("require_lowercase_characters"),
+		RequireUppercase:     res.GetBoolAttr("require_uppercase_characters"),
+		RequireNumbers:       res.GetBoolAttr("require_numbers"),
+		RequireSymbols:       res.GetBoolAttr("require_symbols"),
+		MaxAgeDays:           res.GetIntAttr("max_password_age"),
+		MinimumLength:        res.GetIntAttr("minimum_password_length", 6),
+	}
+}
+
+func adaptPolicies(g *Graph) []iam.Policy {
+	var policies []iam.Policy
+	for _, res := range g.FindResourcesByType("aws_iam_policy") {
+		policies = append(policies, adaptPolicy(res))
+	}
+	return policies
+}
+
+func adaptPolicy(res *Node) iam.Policy {
+	document, ok := adaptPolicyDocument(res, "policy")
+	if !ok {
+		document = iam.Document{Metadata: res.Metadata()}
+	}
+
+	return iam.Policy{
+		Metadata: res.Metadata(),
+		Name:     res.GetStringAttr("name"),
+		Document: document,
+		Builtin:  types.Bool(isBuiltinPolicyARN(res.GetStringAttr("arn").Value()), res.Metadata()),
+	}
+}
+
+// adaptPolicyARN adapts the policy that is not managed by the plan, only its name is known from the ARN
+func adaptPolicyARN(arn string, metadata types.Metadata) iam.Policy {
+	return iam.Policy{
+		Metadata: metadata,
+		Name:     types.String(arn[strings.LastIndex(arn, "/")+1:], metadata),
+		Document: iam.Document{Metadata: metadata},
+		Builtin:  types.Bool(isBuiltinPolicyARN(arn), metadata),
+	}
+}
+
+// isBuiltinPolicyARN reports whether the policy is managed by AWS, e.g. arn:aws:iam::aws:policy/ReadOnlyAccess
+func isBuiltinPolicyARN(arn string) bool {
+	return strings.Contains(arn, ":iam::aws:policy/")
+}
+
+// adaptPrincipalPolicies adapts the inline and attached policies of the role, user or group
+func adaptPrincipalPolicies(res *Node, principal string) []iam.Policy {
+	var policies []iam.Policy
+
+	for _, inlinePolicy := range res.FindAllBackRelated("aws_iam_"+principal+"_policy", principal, "name", "id") {
+		document, ok := adaptPolicyDocument(inlinePolicy, "policy")
+		if !ok {
+			document = iam.Document{Metadata: inlinePolicy.Metadata()}
+		}
+		policies = append(policies, iam.Policy{
+			Metadata: inlinePolicy.Metadata(),
+			Name:     inlinePolicy.GetStringAttr("name"),
+			Document: document,
+			Builtin:  types.Bool(false, inlinePolicy.Metadata()),
+		})
+	}
+
+	for _, attachmentType := range []struct {
+		resourceType string
+		attr         string
+	}{
+		{"aws_iam_" + principal + "_policy_attachment", principal},
+		{"aws_iam_policy_attachment", principal + "s"},
+	} {
+		for _, attachment := range res.FindAllBackRelated(attachmentType.resourceType, attachmentType.attr, "name", "id") {
+			policies = append(policies, adaptPolicyAttachment(attachment, "policy_arn"))
+		}
+	}
+
+	return policies
+}
+
+func adaptPolicyAttachment(attachment *Node, attrName string) iam.Policy {
+	if policy := attachment.FindRelated("aws_iam_policy", attrName, "arn", "id"); policy != nil {
+		return adaptPolicy(policy)
+	}
+	return adaptPolicyARN(attachment.GetStringAttr(attrName).Value(), attachment.Metadata())
+}
+
+func adaptRoles(g *Graph) []iam.Role {
+	var roles []iam.Role
+	for _, res := range g.FindResourcesByType("aws_iam_role") {
+		role := iam.Role{
+			Metadata: res.Metadata(),
+			Name:     res.GetStringAttr("name"),
+		}
+
+		for _, inlinePolicy := range res.GetAttr("inline_policy").ToList() {
+			raw := inlinePolicy.GetStringAttr("policy")
+			if raw.IsEmpty() {
+				continue
+			}
+			document, ok := parsePolicyDocument(raw.Value(), res.Metadata())
+			if !ok {
+				continue
+			}
+			role.Policies = append(role.Policies, iam.Policy{
+				Metadata: res.Metadata(),
+				Name:     inlinePolicy.GetStringAttr("name"),
+				Document: document,
+				Builtin:  types.Bool(false, res.Metadata()),
+			})
+		}
+
+		// the exclusive list of the attached policies
+		managedPolicies := res.FindAllRelated("aws_iam_policy", "managed_policy_arns", "arn", "id")
+		for _, policy := range managedPolicies {
+			role.Policies = append(role.Policies, adaptPolicy(policy))
+		}
+	arns:
+		for _, arn := range res.GetAttr("managed_policy_arns").AsStrings() {
+			for _, policy := range managedPolicies {
+				if policy.GetStringAttr("arn").EqualTo(arn) {
+					continue arns
+				}
+			}
+			role.Policies = append(role.Policies, adaptPolicyARN(arn, res.Metadata()))
+		}
+
+		role.Policies = append(role.Policies, adaptPrincipalPolicies(res, "role")...)
+		roles = append(roles, role)
+	}
+	return roles
+}
+
+func adaptUsers(g *Graph) []iam.User {
+	var users []iam.User
+	for _, res := range g.FindResourcesByType("aws_iam_user") {
+		user := adaptUser(res)
+		for _, group := range findUserGroups(res) {
+			user.Groups = append(user.Groups, adaptGroup(group))
+		}
+		users = append(users, user)
+	}
+	return users
+}
+
+func adaptUser(res *Node) iam.User {
+	user := iam.User{
+		Metadata:   res.Metadata(),
+		Name:       res.GetStringAttr("name"),
+		Policies:   adaptPrincipalPolicies(res, "user"),
+		LastAccess: types.TimeUnresolvable(res.Metadata()),
+	}
+
+	for _, accessKey := range res.FindAllBackRelated("aws_iam_access_key", "user", "name", "id") {
+		user.AccessKeys = append(user.AccessKeys, adaptAccessKey(accessKey))
+	}
+
+	return user
+}
+
+func adaptAccessKey(res *Node) iam.AccessKey {
+	accessKeyID := res.GetStringAttr("id")
+	if accessKeyID.IsEmpty() {
+		accessKeyID = types.StringUnresolvable(res.Metadata())
+	}
+
+	creationDate := types.TimeUnresolvable(res.Metadata())
+	if createDate := res.GetStringAttr("create_date"); createDate.IsNotEmpty() {
+		if parsed, err := time.Parse(time.RFC3339, createDate.Value()); err == nil {
+			creationDate = types.Time(parsed, res.Metadata())
+		}
+	}
+
+	return iam.AccessKey{
+		Metadata:     res.Metadata(),
+		AccessKeyId:  accessKeyID,
+		Active:       types.Bool(res.GetStringAttr("status", "Active").EqualTo("Active"), res.Metadata()),
+		CreationDate: creationDate,
+		LastAccess:   types.TimeUnresolvable(res.Metadata()),
+	}
+}
+
+func adaptGroups(g *Graph) []iam.Group {
+	var groups []iam.Group
+	for _, res := range g.FindResourcesByType("aws_iam_group") {
+		group := adaptGroup(res)
+		for _, user := range findGroupUsers(res) {
+			group.Users = append(group.Users, adaptUser(user))
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+func adaptGroup(res *Node) iam.Group {
+	return iam.Group{
+		Metadata: res.Metadata(),
+		Name:     res.GetStringAttr("name"),
+		Policies: adaptPrincipalPolicies(res, "group"),
+	}
+}
+
+// findUserGroups returns the groups the user is a member of
+func findUserGroups(user *Node) []*Node {
+	var groups []*Node
+	for _, membership := range user.FindAllBackRelated("aws_iam_user_group_membership", "user", "name", "id") {
+		groups = append(groups, membership.FindAllRelated("aws_iam_group", "groups", "name", "id")...)
+	}
+	for _, membership := range user.FindAllBackRelated("aws_iam_group_membership", "users", "name", "id") {
+		if group := membership.FindRelated("aws_iam_group", "group", "name", "id"); group != nil {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// findGroupUsers returns the members of the group
+func findGroupUsers(group *Node) []*Node {
+	var users []*Node
+	for _, membership := range group.FindAllBackRelated("aws_iam_group_membership", "group", "name", "id") {
+		users = append(users, membership.FindAllRelated("aws_iam_user", "users", "name", "id")...)
+	}
+	for _, membership := range group.FindAllBackRelated("aws_iam_user_group_membership", "groups", "name", "id") {
+		if user := membership.FindRelated("aws_iam_user", "user", "name", "id"); user != nil {
+			users = append(users, user)
+		}
+	}
+	return users
+}
@@ -0,0 +1,101 @@
+package tfplanadapt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/iam"
+	"github.com/aquasecurity/defsec/pkg/state"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func TestAdaptIAM(t *testing.T) {
+
+	adminPolicy := iam.Policy{
+		Name: types.String("admin", types.Metadata{}),
+		Document: iam.Document{
+			Parsed: mustParsePolicy(t, `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"*","Resource":"*"}]}`),
+		},
+	}
+
+	readOnlyPolicy := iam.Policy{
+		Name: types.String("ReadOnlyAccess", types.Metadata{}),
+		Document: iam.Document{
+			Parsed: mustParsePolicy(t, `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:Get*","Resource":"*"}]}`),
+		},
+		Builtin: types.Bool(true, types.Metadata{}),
+	}
+
+	developers := iam.Group{
+		Name:     types.String("developers", types.Metadata{}),
+		Policies: []iam.Policy{readOnlyPolicy},
+	}
+
+	alice := iam.User{
+		Name: types.String("alice", types.Metadata{}),
+		Policies: []iam.Policy{
+			{
+				Name: types.String("describe-instances", types.Metadata{}),
+				Document: iam.Document{
+					Parsed: mustParsePolicy(t, `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"ec2:DescribeInstances","Resource":"*"}]}`),
+				},
+			},
+		},
+		AccessKeys: []iam.AccessKey{
+			{
+				AccessKeyId:  types.StringUnresolvable(types.Metadata{}),
+				Active:       types.Bool(true, types.Metadata{}),
+				CreationDate: types.TimeUnresolvable(types.Metadata{}),
+				LastAccess:   types.TimeUnresolvable(types.Metadata{}),
+			},
+		},
+		LastAccess: types.TimeUnresolvable(types.Metadata{}),
+	}
+
+	aliceWithGroups := alice
+	aliceWithGroups.Groups = []iam.Group{developers}
+
+	developersWithUsers := developers
+	developersWithUsers.Users = []iam.User{alice}
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			IAM: iam.IAM{
+				PasswordPolicy: iam.PasswordPolicy{
+					ReusePreventionCount: types.Int(5, types.Metadata{}),
+					RequireLowercase:     types.Bool(true, types.Metadata{}),
+					RequireUppercase:     types.Bool(true, types.Metadata{}),
+					RequireNumbers:       types.Bool(true, types.Metadata{}),
+					RequireSymbols:       types.Bool(true, types.Metadata{}),
+					MaxAgeDays:           types.Int(90, types.Metadata{}),
+					MinimumLength:        types.Int(14, types.Metadata{}),
+				},
+				Policies: []iam.Policy{adminPolicy},
+				Groups:   []iam.Group{developersWithUsers},
+				Users:    []iam.User{aliceWithGroups},
+				Roles: []iam.Role{
+					{
+						Name: types.String("app", types.Metadata{}),
+						Policies: []iam.Policy{
+							{
+								Name: types.String("read-objects", types.Metadata{}),
+								Document: iam.Document{
+									Parsed: mustParsePolicy(t, `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject","Resource":"*"}]}`),
+								},
+							},
+							{
+								Name:    types.String("ReadOnlyAccess", types.Metadata{}),
+								Builtin: types.Bool(true, types.Metadata{}),
+							},
+							adminPolicy,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	runAdaptTest(t, filepath.Join("testdata", "iam", "tfplan.json"), expected)
+}
@@ -0,0 +1,23 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/kinesis"
+)
+
+func adaptKinesis(g *Graph) kinesis.Kinesis {
+	var streams []kinesis.Stream
+	for _, res := range g.FindResourcesByType("aws_kinesis_stream") {
+		streams = append(streams, kinesis.Stream{
+			Metadata: res.Metadata(),
+			Encryption: kinesis.Encryption{
+				Metadata: res.Metadata(),
+				Type:     res.GetStringAttr("encryption_type", "NONE"),
+				KMSKeyID: getKMSKeyID(g, res, "kms_key_id"),
+			},
+		})
+	}
+
+	return kinesis.Kinesis{
+		Streams: streams,
+	}
+}
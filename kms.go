@@ -0,0 +1,129 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/kms"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptKMS(g *Graph) kms.KMS {
+	var keys []kms.Key
+	for _, res := range g.FindResourcesByType("aws_kms_key") {
+		keys = append(keys, kms.Key{
+			Metadata:        res.Metadata(),
+			Usage:           res.GetStringAttr("key_usage", "ENCRYPT_DECRYPT"),
+			RotationEnabled: res.GetBoolAttr("enable_key_rotation"),
+		})
+	}
+
+	return kms.KMS{
+		Keys: keys,
+	}
+}
+
+var (
+	kmsKeyAttrs   = []string{"arn", "key_id", "id"}
+	kmsAliasAttrs = []string{"arn", "name", "id", "target_key_arn", "target_key_id"}
+)
+
+// findKMSKey returns the KMS key referenced by the resource attribute. The key is referenced directly
+// or through an alias
+func findKMSKey(g *Graph, res *Node, field string) (*Node, string) {
+	for _, keyAttr := range kmsKeyAttrs {
+		if key := res.FindRelated("aws_kms_key", field, keyAttr); key != nil {
+			return key, keyAttr
+		}
+	}
+
+	if alias := res.FindRelated("aws_kms_alias", field, kmsAliasAttrs...); alias != nil {
+		if key := findAliasTargetKey(g, alias); key != nil {
+			return key, "arn"
+		}
+	}
+
+	return nil, ""
+}
+
+// findKMSKeyByValue returns the KMS key with the given ARN, ID or alias name
+func findKMSKeyByValue(g *Graph, val string) *Node {
+	// the key may be read by the data source
+	for _, key := range findKMSNodes(g, "aws_kms_key") {
+		for _, keyAttr := range kmsKeyAttrs {
+			if key.GetStringAttr(keyAttr).EqualTo(val) {
+				return key
+			}
+		}
+	}
+
+	for _, alias := range findKMSNodes(g, "aws_kms_alias") {
+		if alias.GetStringAttr("name").EqualTo(val) || alias.GetStringAttr("arn").EqualTo(val) {
+			return findAliasTargetKey(g, alias)
+		}
+	}
+
+	return nil
+}
+
+func findKMSNodes(g *Graph, resourceType string) []*Node {
+	return append(g.FindResourcesByType(resourceType), g.FindDataSourcesByType(resourceType)...)
+}
+
+func findAliasTargetKey(g *Graph, alias *Node) *Node {
+	if key := alias.FindRelated("aws_kms_key", "target_key_id", kmsKeyAttrs...); key != nil {
+		return key
+	}
+	if targetKeyID := alias.GetStringAttr("target_key_id"); targetKeyID.IsNotEmpty() {
+		for _, key := range findKMSNodes(g, "aws_kms_key") {
+			if key.GetStringAttr("key_id").EqualTo(targetKeyID.Value()) ||
+				key.GetStringAttr("arn").EqualTo(targetKeyID.Value()) {
+				return key
+			}
+		}
+	}
+	return nil
+}
+
+// resolveKMSKeyID returns the ARN or ID of the KMS key referenced by the resource attribute.
+// If the key is created by the plan, its ARN is not known and the value is marked as unresolvable
+func resolveKMSKeyID(g *Graph, res *Node, field string) (types.StringValue, bool) {
+	key, keyAttr := findKMSKey(g, res, field)
+	if key == nil {
+		return types.StringDefault("", res.Metadata()), false
+	}
+
+	if keyID := key.GetStringAttr(keyAttr); keyID.IsNotEmpty() {
+		return types.String(keyID.Value(), res.Metadata()), true
+	}
+	return types.StringUnresolvable(res.Metadata()), true
+}
+
+// resolveKMSKeyValue returns the ARN of the KMS key with the given ARN, ID or alias name.
+// The value is returned as is if the key is not read by the plan or its ARN is not known
+func resolveKMSKeyValue(g *Graph, value types.StringValue) types.StringValue {
+	if !value.IsNotEmpty() {
+		return value
+	}
+	if key := findKMSKeyByValue(g, value.Value()); key != nil {
+		if arn := key.GetStringAttr("arn"); arn.IsNotEmpty() {
+			return types.String(arn.Value(), value.GetMetadata())
+		}
+	}
+	return value
+}
+
+// resolveKMSKeyAttr returns the KMS key of the attribute with the given value. The key is resolved
+// by the reference of the resource field if the value is not known, otherwise by the ARN, ID or alias
+func resolveKMSKeyAttr(g *Graph, res *Node, value types.StringValue, field string) types.StringValue {
+	if value.Value() != "" {
+		return resolveKMSKeyValue(g, value)
+	}
+	if resolved, ok := resolveKMSKeyID(g, res, field); ok {
+		return resolved
+	}
+	return value
+}
+
+// getKMSKeyID returns the KMS key of the resource attribute,
+// the key is resolved from the plan if the attribute value is not known
+func getKMSKeyID(g *Graph, res *Node, attrName string) types.StringValue {
+	return resolveKMSKeyAttr(g, res, res.GetStringAttr(attrName), attrName)
+}
@@ -0,0 +1,144 @@
+package tfplanadapt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/ec2"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/kms"
+	"github.com/aquasecurity/defsec/pkg/state"
+	"github.com/aquasecurity/defsec/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdaptKMS(t *testing.T) {
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			KMS: kms.KMS{
+				Keys: []kms.Key{
+					{
+						Usage:           types.String("ENCRYPT_DECRYPT", types.Metadata{}),
+						RotationEnabled: types.Bool(true, types.Metadata{}),
+					},
+					{
+						Usage:           types.String("SIGN_VERIFY", types.Metadata{}),
+						RotationEnabled: types.Bool(false, types.Metadata{}),
+					},
+				},
+			},
+			EC2: ec2.EC2{
+				Volumes: []ec2.Volume{
+					{
+						Encryption: ec2.Encryption{
+							Enabled:  types.Bool(true, types.Metadata{}),
+							KMSKeyID: types.StringUnresolvable(types.Metadata{}),
+						},
+					},
+					{
+						Encryption: ec2.Encryption{
+							Enabled:  types.Bool(true, types.Metadata{}),
+							KMSKeyID: types.String("arn:aws:kms:us-east-1:111111111111:key/0d9e6f2a-existing", types.Metadata{}),
+						},
+					},
+					{
+						Encryption: ec2.Encryption{
+							Enabled:  types.Bool(true, types.Metadata{}),
+							KMSKeyID: types.String("arn:aws:kms:us-east-1:111111111111:key/0d9e6f2a-existing", types.Metadata{}),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	runAdaptTest(t, filepath.Join("testdata", "kms", "tfplan.json"), expected)
+}
+
+func TestFindKMSKey(t *testing.T) {
+	g := readPlanGraph(t, filepath.Join("testdata", "kms", "tfplan.json"))
+
+	tests := []struct {
+		name     string
+		resource string
+		expected string
+	}{
+		{
+			name:     "reference to alias",
+			resource: "aws_ebs_volume.alias",
+			expected: "aws_kms_key.main",
+		},
+		{
+			name:     "reference to data source",
+			resource: "aws_ebs_volume.existing",
+			expected: "data.aws_kms_key.existing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := g.GetResource(tt.resource)
+			require.NotNil(t, res)
+
+			key, _ := findKMSKey(g, res, "kms_key_id")
+			require.NotNil(t, key)
+			assert.Equal(t, tt.expected, key.Address)
+		})
+	}
+
+	t.Run("by alias name", func(t *testing.T) {
+		key := findKMSKeyByValue(g, "alias/main")
+		require.NotNil(t, key)
+		assert.Equal(t, "aws_kms_key.main", key.Address)
+	})
+
+	t.Run("by ARN", func(t *testing.T) {
+		key := findKMSKeyByValue(g, "arn:aws:kms:us-east-1:111111111111:key/0d9e6f2a-existing")
+		require.NotNil(t, key)
+		assert.Equal(t, "data.aws_kms_key.existing", key.Address)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		assert.Nil(t, findKMSKeyByValue(g, "alias/unknown"))
+	})
+}
+
+func TestResolveKMSKeyAttr(t *testing.T) {
+	g := readPlanGraph(t, filepath.Join("testdata", "kms", "tfplan.json"))
+
+	const existingKeyARN = "arn:aws:kms:us-east-1:111111111111:key/0d9e6f2a-existing"
+
+	volume := g.GetResource("aws_ebs_volume.existing")
+	require.NotNil(t, volume)
+
+	tests := []struct {
+		name     string
+		value    types.StringValue
+		expected string
+	}{
+		{
+			name:     "alias name",
+			value:    types.String("alias/existing", volume.Metadata()),
+			expected: existingKeyARN,
+		},
+		{
+			name:     "unknown alias",
+			value:    types.String("alias/unknown", volume.Metadata()),
+			expected: "alias/unknown",
+		},
+		{
+			name:     "reference",
+			value:    types.StringDefault("", volume.Metadata()),
+			expected: existingKeyARN,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resolveKMSKeyAttr(g, volume, tt.value, "kms_key_id").Value())
+		})
+	}
+}
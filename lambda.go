@@ -0,0 +1,64 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/lambda"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptLambda(g *Graph) lambda.Lambda {
+	adoptedPermissions := make(map[string]struct{})
+
+	var functions []lambda.Function
+	for _, res := range g.FindResourcesByType("aws_lambda_function") {
+		function := lambda.Function{
+			Metadata: res.Metadata(),
+			Tracing: lambda.Tracing{
+				Metadata: res.Metadata(),
+				Mode:     res.GetAttr("tracing_config").GetStringAttr("mode"),
+			},
+		}
+
+		if function.Tracing.Mode.IsEmpty() {
+			function.Tracing.Mode = types.StringDefault(lambda.TracingModePassThrough, res.Metadata())
+		}
+
+		for _, permission := range res.FindAllBackRelatedOrMatching(
+			g, "aws_lambda_permission", "function_name", "function_name", "arn", "qualified_arn", "id",
+		) {
+			function.Permissions = append(function.Permissions, adaptLambdaPermission(permission))
+			adoptedPermissions[permission.ID()] = struct{}{}
+		}
+
+		functions = append(functions, function)
+	}
+
+	// permissions of functions that are not managed by the plan
+	for _, permission := range g.FindResourcesByType("aws_lambda_permission") {
+		if _, adopted := adoptedPermissions[permission.ID()]; adopted {
+			continue
+		}
+
+		// only the permission is managed by the plan
+		metadata := types.NewUnmanagedMetadata()
+		functions = append(functions, lambda.Function{
+			Metadata: metadata,
+			Tracing: lambda.Tracing{
+				Metadata: metadata,
+				Mode:     types.StringDefault("", metadata),
+			},
+			Permissions: []lambda.Permission{adaptLambdaPermission(permission)},
+		})
+	}
+
+	return lambda.Lambda{
+		Functions: functions,
+	}
+}
+
+func adaptLambdaPermission(res *Node) lambda.Permission {
+	return lambda.Permission{
+		Metadata:  res.Metadata(),
+		Principal: res.GetStringAttr("principal"),
+		SourceARN: res.getReferencedStringAttr("source_arn"),
+	}
+}
@@ -0,0 +1,79 @@
+package tfplanadapt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/lambda"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/s3"
+	"github.com/aquasecurity/defsec/pkg/state"
+	"github.com/aquasecurity/defsec/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptLambda(t *testing.T) {
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			S3: s3.S3{
+				Buckets: []s3.Bucket{
+					{
+						Name: types.String("uploads", types.Metadata{}),
+					},
+				},
+			},
+			Lambda: lambda.Lambda{
+				Functions: []lambda.Function{
+					{
+						Tracing: lambda.Tracing{
+							Mode: types.String("Active", types.Metadata{}),
+						},
+						Permissions: []lambda.Permission{
+							{
+								Principal: types.String("apigateway.amazonaws.com", types.Metadata{}),
+							},
+						},
+					},
+					{
+						Tracing: lambda.Tracing{
+							Mode: types.String("PassThrough", types.Metadata{}),
+						},
+						Permissions: []lambda.Permission{
+							{
+								Principal: types.String("s3.amazonaws.com", types.Metadata{}),
+								SourceARN: types.StringUnresolvable(types.Metadata{}),
+							},
+						},
+					},
+					{
+						Permissions: []lambda.Permission{
+							{
+								Principal: types.String("events.amazonaws.com", types.Metadata{}),
+								SourceARN: types.String("arn:aws:events:us-east-1:111111111111:rule/nightly", types.Metadata{}),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got := runAdaptTest(t, filepath.Join("testdata", "lambda", "tfplan.json"), expected)
+
+	// the source ARN refers to the bucket created by the plan
+	sourceARN := got.AWS.Lambda.Functions[1].Permissions[0].SourceARN
+	assert.False(t, sourceARN.GetMetadata().IsResolvable())
+	assert.False(t, sourceARN.IsEmpty())
+
+	// the function of the external permission is not managed by the plan
+	external := got.AWS.Lambda.Functions[2]
+	assert.True(t, external.Metadata.IsUnmanaged())
+	assert.Equal(t, "aws_lambda_permission.external", external.Permissions[0].Metadata.Reference())
+	for _, result := range Scan(got) {
+		if result.Rule().AVDID == "AVD-AWS-0066" {
+			assert.NotEqual(t, "aws_lambda_permission.external", result.Metadata().Reference())
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package tfplanadapt
+
+import (
+	"strconv"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws/mq"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptMQ(g *Graph) mq.MQ {
+	var brokers []mq.Broker
+	for _, res := range g.FindResourcesByType("aws_mq_broker") {
+		logs := res.GetAttr("logs")
+		brokers = append(brokers, mq.Broker{
+			Metadata:     res.Metadata(),
+			PublicAccess: res.GetBoolAttr("publicly_accessible"),
+			Logging: mq.Logging{
+				Metadata: res.Metadata(),
+				General:  logs.GetBoolAttr("general"),
+				Audit:    adaptBrokerAuditLogging(res),
+			},
+		})
+	}
+
+	return mq.MQ{
+		Brokers: brokers,
+	}
+}
+
+// adaptBrokerAuditLogging returns the audit logging flag of the broker,
+// the provider stores this flag as a nullable boolean string
+func adaptBrokerAuditLogging(res *Node) types.BoolValue {
+	audit := res.GetAttr("logs").GetNestedAttr("audit")
+	if val := audit.AsBool(); val != nil {
+		return types.Bool(*val, res.Metadata())
+	}
+	if val := audit.AsString(); val != nil {
+		if enabled, err := strconv.ParseBool(*val); err == nil {
+			return types.Bool(enabled, res.Metadata())
+		}
+	}
+	return types.BoolDefault(false, res.Metadata())
+}
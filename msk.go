@@ -0,0 +1,76 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/msk"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptMSK(g *Graph) msk.MSK {
+	var clusters []msk.Cluster
+	for _, res := range g.FindResourcesByType("aws_msk_cluster") {
+		clusters = append(clusters, msk.Cluster{
+			Metadata:            res.Metadata(),
+			EncryptionInTransit: adaptMSKEncryptionInTransit(res),
+			EncryptionAtRest:    adaptMSKEncryptionAtRest(g, res),
+			Logging:             adaptMSKLogging(res),
+		})
+	}
+
+	return msk.MSK{
+		Clusters: clusters,
+	}
+}
+
+func adaptMSKEncryptionInTransit(res *Node) msk.EncryptionInTransit {
+	encryption := msk.EncryptionInTransit{
+		Metadata:     res.Metadata(),
+		ClientBroker: types.StringDefault(msk.ClientBrokerEncryptionTLS, res.Metadata()),
+	}
+
+	// TLS is used between clients and brokers unless configured otherwise
+	clientBroker := res.GetNestedAttr("encryption_info.encryption_in_transit.client_broker").AsString()
+	if clientBroker != nil && *clientBroker != "" {
+		encryption.ClientBroker = types.String(*clientBroker, res.Metadata())
+	}
+
+	return encryption
+}
+
+func adaptMSKEncryptionAtRest(g *Graph, res *Node) msk.EncryptionAtRest {
+	const keyAttr = "encryption_info.encryption_at_rest_kms_key_arn"
+
+	encryption := msk.EncryptionAtRest{
+		Metadata:  res.Metadata(),
+		KMSKeyARN: resolveKMSKeyAttr(g, res, res.getReferencedStringAttr(keyAttr), keyAttr),
+		Enabled:   types.BoolDefault(false, res.Metadata()),
+	}
+
+	// the cluster is encrypted with the customer managed key
+	if encryption.KMSKeyARN.IsNotEmpty() || !encryption.KMSKeyARN.GetMetadata().IsResolvable() {
+		encryption.Enabled = types.Bool(true, res.Metadata())
+	}
+
+	return encryption
+}
+
+func adaptMSKLogging(res *Node) msk.Logging {
+	brokerLogs := res.GetNestedAttr("logging_info.broker_logs")
+	return msk.Logging{
+		Metadata: res.Metadata(),
+		Broker: msk.BrokerLogging{
+			Metadata: res.Metadata(),
+			S3: msk.S3Logging{
+				Metadata: res.Metadata(),
+				Enabled:  brokerLogs.GetBoolAttr("s3.enabled"),
+			},
+			Cloudwatch: msk.CloudwatchLogging{
+				Metadata: res.Metadata(),
+				Enabled:  brokerLogs.GetBoolAttr("cloudwatch_logs.enabled"),
+			},
+			Firehose: msk.FirehoseLogging{
+				Metadata: res.Metadata(),
+				Enabled:  brokerLogs.GetBoolAttr("firehose.enabled"),
+			},
+		},
+	}
+}
@@ -0,0 +1,28 @@
+package tfplanadapt
+
+import (
+	"slices"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws/neptune"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptNeptune(g *Graph) neptune.Neptune {
+	var clusters []neptune.Cluster
+	for _, res := range g.FindResourcesByType("aws_neptune_cluster") {
+		logExports := res.GetAttr("enable_cloudwatch_logs_exports").AsStrings()
+		clusters = append(clusters, neptune.Cluster{
+			Metadata: res.Metadata(),
+			Logging: neptune.Logging{
+				Metadata: res.Metadata(),
+				Audit:    types.Bool(slices.Contains(logExports, "audit"), res.Metadata()),
+			},
+			StorageEncrypted: res.GetBoolAttr("storage_encrypted"),
+			KMSKeyID:         getKMSKeyID(g, res, "kms_key_arn"),
+		})
+	}
+
+	return neptune.Neptune{
+		Clusters: clusters,
+	}
+}
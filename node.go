@@ -10,11 +10,12 @@ import (
 type Node struct {
 	resourceType string
 	resourceName string
-	moduleName   string
+	modulePath   string
 	Address      string
 	neighbors    []*Edge
 	backLinks    []*Edge
 	attributes   map[string]*Attribute
+	provider     *providerConfig
 }
 
 // FindRelated searches for a related resource given the resource type
@@ -32,6 +33,22 @@ func (node *Node) FindRelated(toResource, fromAttr string, toAttrs ...string) *N
 	return nil
 }
 
+// FindAllRelated searches for all related resources given the resource type
+func (node *Node) FindAllRelated(toResource, fromAttr string, toAttrs ...string) []*Node {
+	var result []*Node
+	for _, neighbor := range node.neighbors {
+		if neighbor.to.resourceType == toResource {
+			for _, attr := range toAttrs {
+				if neighbor.linkAttributes[fromAttr] == attr {
+					result = append(result, neighbor.to)
+					break
+				}
+			}
+		}
+	}
+	return result
+}
+
 // FindBackRelated searches for a backward-linked resource given the resource type
 func (node *Node) FindBackRelated(toResource, fromAttr string, toAttrs ...string) *Node {
 	for _, backLink := range node.backLinks {
@@ -46,12 +63,145 @@ func (node *Node) FindBackRelated(toResource, fromAttr string, toAttrs ...string
 	return nil
 }
 
+// FindAllBackRelated searches for all backward-linked resources given the resource type
+func (node *Node) FindAllBackRelated(toResource, fromAttr string, toAttrs ...string) []*Node {
+	var result []*Node
+	for _, backLink := range node.backLinks {
+		if backLink.from.resourceType == toResource {
+			for _, attr := range toAttrs {
+				if backLink.linkAttributes[fromAttr] == attr {
+					result = append(result, backLink.from)
+					break
+				}
+			}
+		}
+	}
+	return result
+}
+
+// FindAllBackRelatedOrMatching searches for all backward-linked resources given the resource type
+// and for the resources whose attribute holds the value of one of the target attributes.
+// The latter are resources that refer to the node by a literal value instead of an expression
+func (node *Node) FindAllBackRelatedOrMatching(g *Graph, toResource, fromAttr string, toAttrs ...string) []*Node {
+	result := node.FindAllBackRelated(toResource, fromAttr, toAttrs...)
+	for _, res := range g.FindResourcesByType(toResource) {
+		value := res.GetStringAttr(fromAttr)
+		if value.IsEmpty() || containsNode(result, res) {
+			continue
+		}
+		for _, attr := range toAttrs {
+			if node.GetStringAttr(attr).EqualTo(value.Value()) {
+				result = append(result, res)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func containsNode(nodes []*Node, node *Node) bool {
+	for _, n := range nodes {
+		if n.ID() == node.ID() {
+			return true
+		}
+	}
+	return false
+}
+
+// region returns the region of the resource, the region of the provider is used
+// if the resource does not override it
+func (n *Node) region() string {
+	if region := n.GetAttr("region").AsString(); region != nil && *region != "" {
+		return *region
+	}
+	if n.provider == nil {
+		return ""
+	}
+	return n.provider.region
+}
+
+// Tags returns the tags of the resource merged with the default tags of the provider,
+// the resource tags take precedence
+func (n *Node) Tags() map[string]string {
+	tags := make(map[string]string)
+	if n.provider != nil {
+		for key, val := range n.provider.defaultTags {
+			tags[key] = val
+		}
+	}
+
+	for key, val := range n.GetAttr("tags").AsStringMap() {
+		tags[key] = val
+	}
+	return tags
+}
+
+// accountID returns the account of the provider that manages the resource
+func (n *Node) accountID() string {
+	if n.provider == nil {
+		return ""
+	}
+	return n.provider.accountID
+}
+
+// sameAccount reports whether the resources are managed in the same account.
+// Unknown accounts are considered the same
+func (n *Node) sameAccount(other *Node) bool {
+	accountID, otherAccountID := n.accountID(), other.accountID()
+	return accountID == "" || otherAccountID == "" || accountID == otherAccountID
+}
+
+// sameRegion reports whether the resources are managed in the same region of the same account
+func (n *Node) sameRegion(other *Node) bool {
+	return n.sameAccount(other) && n.region() == other.region()
+}
+
+// isDataSource reports whether the node is a data source, e.g. module.example.data.aws_iam_policy.this
+func (n *Node) isDataSource() bool {
+	parts := splitAddress(n.Address)
+	for len(parts) > 2 && parts[0] == "module" {
+		parts = parts[2:]
+	}
+	return parts[0] == "data"
+}
+
+// hasReference reports whether the attribute refers to another resource,
+// the value of such an attribute may not be known until apply
+func (n *Node) hasReference(attrPath string) bool {
+	for _, neighbor := range n.neighbors {
+		if _, exists := neighbor.linkAttributes[attrPath]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+// getReferencedStringAttr returns the value of the nested attribute. If the attribute refers
+// to another resource and its value is not known, the value is unresolvable
+func (n *Node) getReferencedStringAttr(attrPath string) defsecTypes.StringValue {
+	if val := n.GetNestedAttr(attrPath).AsString(); val != nil && *val != "" {
+		return defsecTypes.String(*val, n.Metadata())
+	}
+	if n.hasReference(attrPath) {
+		return defsecTypes.StringUnresolvable(n.Metadata())
+	}
+	return defsecTypes.StringDefault("", n.Metadata())
+}
+
 func (n *Node) ID() string {
 	return n.Address
 }
 
+// Metadata returns the metadata of the resource, the resource address is used as a reference
+func (n *Node) Metadata() defsecTypes.Metadata {
+	return defsecTypes.NewMetadata(defsecTypes.NewRange("", 0, 0, "", nil), n.Address)
+}
+
 func (b *Node) GetAttr(name string) *Attribute {
-	return b.attributes[name]
+	if attr, exists := b.attributes[name]; exists {
+		return attr
+	}
+	return &Attribute{metadata: b.Metadata()}
 }
 
 func (b *Node) GetNestedAttr(path string) *Attribute {
@@ -61,8 +211,8 @@ func (b *Node) GetNestedAttr(path string) *Attribute {
 
 	parts := strings.SplitN(path, ".", 2)
 	attr := b.GetAttr(parts[0])
-	if attr == nil {
-		return nil
+	if attr.IsNil() {
+		return attr
 	}
 	if len(parts) == 1 {
 		return attr
@@ -71,7 +221,7 @@ func (b *Node) GetNestedAttr(path string) *Attribute {
 }
 
 func (b *Node) GetBoolAttr(name string, defValue ...bool) defsecTypes.BoolValue {
-	def := defsecTypes.BoolDefault(firstOrDefault(defValue), defsecTypes.Metadata{})
+	def := defsecTypes.BoolDefault(firstOrDefault(defValue), b.Metadata())
 	attr, exists := b.attributes[name]
 	if !exists {
 		return def
@@ -81,11 +231,11 @@ func (b *Node) GetBoolAttr(name string, defValue ...bool) defsecTypes.BoolValue
 		return def
 	}
 
-	return defsecTypes.Bool(*val, defsecTypes.Metadata{})
+	return defsecTypes.Bool(*val, b.Metadata())
 }
 
 func (b *Node) GetStringAttr(name string, defValue ...string) defsecTypes.StringValue {
-	def := defsecTypes.StringDefault(firstOrDefault(defValue), defsecTypes.Metadata{})
+	def := defsecTypes.StringDefault(firstOrDefault(defValue), b.Metadata())
 	attr, exists := b.attributes[name]
 	if !exists {
 		return def
@@ -95,7 +245,21 @@ func (b *Node) GetStringAttr(name string, defValue ...string) defsecTypes.String
 		return def
 	}
 
-	return defsecTypes.String(*val, defsecTypes.Metadata{})
+	return defsecTypes.String(*val, b.Metadata())
+}
+
+func (b *Node) GetIntAttr(name string, defValue ...int) defsecTypes.IntValue {
+	def := defsecTypes.IntDefault(firstOrDefault(defValue), b.Metadata())
+	attr, exists := b.attributes[name]
+	if !exists {
+		return def
+	}
+	val := attr.AsInt()
+	if val == nil {
+		return def
+	}
+
+	return defsecTypes.Int(*val, b.Metadata())
 }
 
 func firstOrDefault[T any](a []T) T {
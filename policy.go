@@ -0,0 +1,37 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/iam"
+	"github.com/aquasecurity/defsec/pkg/types"
+	"github.com/liamg/iamgo"
+)
+
+// adaptPolicyDocument parses the IAM policy document from the resource attribute.
+// If the document is not known, it is taken from the related data.aws_iam_policy_document
+func adaptPolicyDocument(res *Node, attrName string) (iam.Document, bool) {
+	raw := res.GetAttr(attrName).AsString()
+	if raw == nil || *raw == "" {
+		policyDocument := res.FindRelated("aws_iam_policy_document", attrName, "json", "minified_json")
+		if policyDocument == nil {
+			return iam.Document{}, false
+		}
+		raw = policyDocument.GetAttr("json").AsString()
+		if raw == nil || *raw == "" {
+			return iam.Document{}, false
+		}
+	}
+
+	return parsePolicyDocument(*raw, res.Metadata())
+}
+
+func parsePolicyDocument(raw string, metadata types.Metadata) (iam.Document, bool) {
+	parsed, err := iamgo.ParseString(raw)
+	if err != nil {
+		return iam.Document{}, false
+	}
+
+	return iam.Document{
+		Metadata: metadata,
+		Parsed:   *parsed,
+	}, true
+}
@@ -0,0 +1,209 @@
+package tfplanadapt
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws"
+	"github.com/aquasecurity/defsec/pkg/types"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// providerConfig represents the configuration of the provider that manages the resource.
+// The empty region or account means that it is not known from the plan,
+// e.g. it comes from the environment.
+type providerConfig struct {
+	key               string
+	name              string
+	alias             string
+	region            string
+	accountID         string
+	allowedAccountIDs []string
+	assumeRole        *assumeRole
+	defaultTags       map[string]string
+}
+
+type assumeRole struct {
+	roleARN     string
+	sessionName string
+	externalID  string
+}
+
+func (c *providerConfig) metadata() types.Metadata {
+	return types.NewMetadata(types.NewRange("", 0, 0, "", nil), "provider."+c.key)
+}
+
+// parseProviderConfigs parses the provider configurations of the plan indexed by the provider config key
+func parseProviderConfigs(plan *tfjson.Plan) map[string]*providerConfig {
+	configs := make(map[string]*providerConfig)
+	if plan.Config == nil {
+		return configs
+	}
+
+	for key, config := range plan.Config.ProviderConfigs {
+		if config == nil {
+			continue
+		}
+
+		// variables are only known for the root module
+		var variables map[string]*tfjson.PlanVariable
+		if config.ModuleAddress == "" {
+			variables = plan.Variables
+		}
+
+		provider := &providerConfig{
+			key:               key,
+			name:              config.Name,
+			alias:             config.Alias,
+			region:            resolveStringExpression(config.Expressions["region"], variables),
+			allowedAccountIDs: resolveStringsExpression(config.Expressions["allowed_account_ids"], variables),
+			defaultTags: resolveStringMapExpression(
+				nestedBlockExpression(config.Expressions["default_tags"], "tags"), variables,
+			),
+		}
+
+		if expr := config.Expressions["assume_role"]; expr != nil && len(expr.NestedBlocks) > 0 {
+			provider.assumeRole = &assumeRole{
+				roleARN:     resolveStringExpression(expr.NestedBlocks[0]["role_arn"], variables),
+				sessionName: resolveStringExpression(expr.NestedBlocks[0]["session_name"], variables),
+				externalID:  resolveStringExpression(expr.NestedBlocks[0]["external_id"], variables),
+			}
+		}
+
+		provider.accountID = resolveProviderAccountID(provider)
+		configs[key] = provider
+	}
+	return configs
+}
+
+// resolveProviderAccountID returns the account the provider is restricted to,
+// or the account of the assumed role
+func resolveProviderAccountID(provider *providerConfig) string {
+	if len(provider.allowedAccountIDs) == 1 {
+		return provider.allowedAccountIDs[0]
+	}
+
+	if provider.assumeRole != nil {
+		// arn:aws:iam::123456789012:role/name
+		if parts := strings.Split(provider.assumeRole.roleARN, ":"); len(parts) > 4 {
+			return parts[4]
+		}
+	}
+
+	return ""
+}
+
+func nestedBlockExpression(expr *tfjson.Expression, name string) *tfjson.Expression {
+	if expr == nil || len(expr.NestedBlocks) == 0 {
+		return nil
+	}
+	return expr.NestedBlocks[0][name]
+}
+
+// resolveExpression returns the constant value of the expression or the value of the referenced variable
+func resolveExpression(expr *tfjson.Expression, variables map[string]*tfjson.PlanVariable) any {
+	if expr == nil {
+		return nil
+	}
+
+	if expr.ConstantValue != nil && expr.ConstantValue != tfjson.UnknownConstantValue {
+		return expr.ConstantValue
+	}
+
+	for _, ref := range expr.References {
+		name, found := strings.CutPrefix(ref, "var.")
+		if !found {
+			continue
+		}
+		if variable, exists := variables[name]; exists && variable != nil {
+			return variable.Value
+		}
+	}
+
+	return nil
+}
+
+func resolveStringExpression(expr *tfjson.Expression, variables map[string]*tfjson.PlanVariable) string {
+	val, _ := resolveExpression(expr, variables).(string)
+	return val
+}
+
+func resolveStringsExpression(expr *tfjson.Expression, variables map[string]*tfjson.PlanVariable) []string {
+	return (&Attribute{val: resolveExpression(expr, variables)}).AsStrings()
+}
+
+func resolveStringMapExpression(expr *tfjson.Expression, variables map[string]*tfjson.PlanVariable) map[string]string {
+	val, ok := resolveExpression(expr, variables).(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	res := make(map[string]string, len(val))
+	for k, v := range val {
+		if s, ok := v.(string); ok {
+			res[k] = s
+		}
+	}
+	return res
+}
+
+// fillProviders links the resources with the configurations of the providers that manage them
+func fillProviders(g *Graph, module configModule, providers map[string]*providerConfig) {
+	if module.ConfigModule == nil {
+		return
+	}
+
+	for _, resource := range module.Resources {
+		provider, exists := providers[resource.ProviderConfigKey]
+		if !exists {
+			continue
+		}
+		for _, node := range findConfigResourceNodes(g, module.address, resource) {
+			node.provider = provider
+		}
+	}
+
+	for callName, moduleCall := range module.ModuleCalls {
+		fillProviders(g, module.child(callName, moduleCall), providers)
+	}
+}
+
+func adaptProviders(g *Graph) []aws.TerraformProvider {
+	var providers []aws.TerraformProvider
+	for _, provider := range g.providers {
+		if provider.name != "aws" {
+			continue
+		}
+
+		meta := provider.metadata()
+		tfProvider := aws.TerraformProvider{
+			Metadata: meta,
+			Alias:    types.String(provider.alias, meta),
+			Region:   types.String(provider.region, meta),
+			DefaultTags: aws.DefaultTags{
+				Metadata: meta,
+				Tags:     types.Map(provider.defaultTags, meta),
+			},
+			AssumeRole: aws.AssumeRole{
+				Metadata: meta,
+			},
+		}
+
+		for _, accountID := range provider.allowedAccountIDs {
+			tfProvider.AllowedAccountsIDs = append(tfProvider.AllowedAccountsIDs, types.String(accountID, meta))
+		}
+
+		if provider.assumeRole != nil {
+			tfProvider.AssumeRole.RoleARN = types.String(provider.assumeRole.roleARN, meta)
+			tfProvider.AssumeRole.SessionName = types.String(provider.assumeRole.sessionName, meta)
+			tfProvider.AssumeRole.ExternalID = types.String(provider.assumeRole.externalID, meta)
+		}
+
+		providers = append(providers, tfProvider)
+	}
+
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].Metadata.Reference() < providers[j].Metadata.Reference()
+	})
+	return providers
+}
@@ -0,0 +1,131 @@
+package tfplanadapt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/ec2"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/s3"
+	"github.com/aquasecurity/defsec/pkg/state"
+	"github.com/aquasecurity/defsec/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptMultiRegion(t *testing.T) {
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			S3: s3.S3{
+				Buckets: []s3.Bucket{
+					{
+						Name:           types.String("audit", types.Metadata{}),
+						BucketLocation: types.String("us-east-1", types.Metadata{}),
+						PublicAccessBlock: &s3.PublicAccessBlock{
+							BlockPublicACLs: types.Bool(true, types.Metadata{}),
+						},
+					},
+					{
+						Name:           types.String("main", types.Metadata{}),
+						BucketLocation: types.String("us-east-1", types.Metadata{}),
+					},
+				},
+			},
+			EC2: ec2.EC2{
+				Volumes: []ec2.Volume{
+					{
+						// the encryption by default is enabled in another account
+						Encryption: ec2.Encryption{
+							Enabled: types.Bool(false, types.Metadata{}),
+						},
+					},
+					{
+						Encryption: ec2.Encryption{
+							Enabled: types.Bool(true, types.Metadata{}),
+						},
+					},
+					{
+						// the encryption by default is enabled in another region
+						Encryption: ec2.Encryption{
+							Enabled: types.Bool(false, types.Metadata{}),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// the provider configurations are checked separately, see TestAdaptProviders
+	runAdaptTest(t, filepath.Join("testdata", "multi_region", "tfplan.json"), expected, ignoreProviders)
+}
+
+func TestAdaptProviders(t *testing.T) {
+	got := Adapt(readPlanGraph(t, filepath.Join("testdata", "multi_region", "tfplan.json")))
+
+	expected := aws.Meta{
+		TFProviders: []aws.TerraformProvider{
+			{
+				Region: types.String("us-east-1", types.Metadata{}),
+				AllowedAccountsIDs: types.StringValueList{
+					types.String("111111111111", types.Metadata{}),
+				},
+				DefaultTags: aws.DefaultTags{
+					Tags: types.Map(map[string]string{
+						"Environment": "prod",
+						"Team":        "platform",
+					}, types.Metadata{}),
+				},
+			},
+			{
+				Alias:  types.String("audit", types.Metadata{}),
+				Region: types.String("us-east-1", types.Metadata{}),
+				AssumeRole: aws.AssumeRole{
+					RoleARN: types.String("arn:aws:iam::222222222222:role/terraform", types.Metadata{}),
+				},
+				DefaultTags: aws.DefaultTags{
+					Tags: types.Map(nil, types.Metadata{}),
+				},
+			},
+			{
+				Alias:  types.String("west", types.Metadata{}),
+				Region: types.String("us-west-2", types.Metadata{}),
+				AllowedAccountsIDs: types.StringValueList{
+					types.String("111111111111", types.Metadata{}),
+				},
+				DefaultTags: aws.DefaultTags{
+					Tags: types.Map(nil, types.Metadata{}),
+				},
+			},
+		},
+	}
+
+	assert.Empty(t, diff(expected, got.AWS.Meta))
+}
+
+func TestNodeTags(t *testing.T) {
+	g := readPlanGraph(t, filepath.Join("testdata", "multi_region", "tfplan.json"))
+
+	// the resource tags override the default tags of the provider
+	assert.Equal(t, map[string]string{
+		"Environment": "prod",
+		"Team":        "storage",
+	}, g.GetResource("aws_s3_bucket.main").Tags())
+
+	assert.Empty(t, g.GetResource("aws_s3_bucket.audit").Tags())
+}
+
+func TestNodeSameAccount(t *testing.T) {
+	var (
+		unknown = &Node{}
+		prod    = &Node{provider: &providerConfig{accountID: "111111111111"}}
+		dev     = &Node{provider: &providerConfig{accountID: "222222222222"}}
+	)
+
+	assert.True(t, prod.sameAccount(prod))
+	assert.False(t, prod.sameAccount(dev))
+
+	// the unknown account may be any account
+	assert.True(t, unknown.sameAccount(unknown))
+	assert.True(t, unknown.sameAccount(prod))
+	assert.True(t, prod.sameAccount(unknown))
+}
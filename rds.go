@@ -0,0 +1,235 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/rds"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptRDS(g *Graph) rds.RDS {
+	return rds.RDS{
+		Instances:       adaptRDSInstances(g),
+		Clusters:        adaptRDSClusters(g),
+		Classic:         adaptRDSClassic(g),
+		Snapshots:       adaptRDSSnapshots(g),
+		ParameterGroups: adaptRDSParameterGroups(g),
+	}
+}
+
+func adaptRDSInstances(g *Graph) []rds.Instance {
+	var instances []rds.Instance
+	for _, res := range g.FindResourcesByType("aws_db_instance") {
+		instances = append(instances, adaptRDSInstance(g, res))
+	}
+	return instances
+}
+
+func adaptRDSInstance(g *Graph, res *Node) rds.Instance {
+	instance := rds.Instance{
+		Metadata:                  res.Metadata(),
+		BackupRetentionPeriodDays: res.GetIntAttr("backup_retention_period"),
+		ReplicationSourceARN:      res.GetStringAttr("replicate_source_db"),
+		PerformanceInsights:       adaptPerformanceInsights(g, res),
+		Encryption: rds.Encryption{
+			Metadata:       res.Metadata(),
+			EncryptStorage: res.GetBoolAttr("storage_encrypted"),
+			KMSKeyID:       getKMSKeyID(g, res, "kms_key_id"),
+		},
+		PublicAccess:            res.GetBoolAttr("publicly_accessible"),
+		Engine:                  res.GetStringAttr("engine"),
+		IAMAuthEnabled:          res.GetBoolAttr("iam_database_authentication_enabled"),
+		DeletionProtection:      res.GetBoolAttr("deletion_protection"),
+		DBInstanceArn:           res.GetStringAttr("arn"),
+		StorageEncrypted:        res.GetBoolAttr("storage_encrypted"),
+		DBInstanceIdentifier:    res.GetStringAttr("identifier"),
+		EngineVersion:           res.GetStringAttr("engine_version"),
+		AutoMinorVersionUpgrade: res.GetBoolAttr("auto_minor_version_upgrade", true),
+		MultiAZ:                 res.GetBoolAttr("multi_az"),
+		PubliclyAccessible:      res.GetBoolAttr("publicly_accessible"),
+		LatestRestorableTime:    types.TimeUnresolvable(res.Metadata()),
+	}
+
+	if parameterGroupName := res.GetStringAttr("parameter_group_name"); parameterGroupName.IsNotEmpty() {
+		instance.DBParameterGroups = append(instance.DBParameterGroups, rds.DBParameterGroupsList{
+			Metadata:             res.Metadata(),
+			DBParameterGroupName: parameterGroupName,
+			KMSKeyID:             types.StringDefault("", res.Metadata()),
+		})
+	} else if parameterGroup := res.FindRelated(
+		"aws_db_parameter_group", "parameter_group_name", "name", "id",
+	); parameterGroup != nil {
+		instance.DBParameterGroups = append(instance.DBParameterGroups, rds.DBParameterGroupsList{
+			Metadata:             parameterGroup.Metadata(),
+			DBParameterGroupName: parameterGroup.GetStringAttr("name"),
+			KMSKeyID:             types.StringDefault("", parameterGroup.Metadata()),
+		})
+	}
+
+	for _, logExport := range res.GetAttr("enabled_cloudwatch_logs_exports").AsStrings() {
+		instance.EnabledCloudwatchLogsExports = append(instance.EnabledCloudwatchLogsExports, types.String(logExport, res.Metadata()))
+	}
+
+	for _, replica := range res.FindAllBackRelated("aws_db_instance", "replicate_source_db", "identifier", "id", "arn") {
+		instance.ReadReplicaDBInstanceIdentifiers = append(
+			instance.ReadReplicaDBInstanceIdentifiers, replica.GetStringAttr("identifier"),
+		)
+	}
+
+	return instance
+}
+
+func adaptPerformanceInsights(g *Graph, res *Node) rds.PerformanceInsights {
+	return rds.PerformanceInsights{
+		Metadata: res.Metadata(),
+		Enabled:  res.GetBoolAttr("performance_insights_enabled"),
+		KMSKeyID: getKMSKeyID(g, res, "performance_insights_kms_key_id"),
+	}
+}
+
+func adaptRDSClusters(g *Graph) []rds.Cluster {
+	adoptedInstances := make(map[string]struct{})
+
+	var clusters []rds.Cluster
+	for _, res := range g.FindResourcesByType("aws_rds_cluster") {
+		cluster := rds.Cluster{
+			Metadata:                  res.Metadata(),
+			BackupRetentionPeriodDays: res.GetIntAttr("backup_retention_period", 1),
+			ReplicationSourceARN:      res.GetStringAttr("replication_source_identifier"),
+			PerformanceInsights:       adaptPerformanceInsights(g, res),
+			Encryption: rds.Encryption{
+				Metadata:       res.Metadata(),
+				EncryptStorage: res.GetBoolAttr("storage_encrypted"),
+				KMSKeyID:       getKMSKeyID(g, res, "kms_key_id"),
+			},
+			PublicAccess:         types.BoolDefault(false, res.Metadata()),
+			Engine:               res.GetStringAttr("engine", rds.EngineAurora),
+			LatestRestorableTime: types.TimeUnresolvable(res.Metadata()),
+			DeletionProtection:   res.GetBoolAttr("deletion_protection"),
+			SkipFinalSnapshot:    res.GetBoolAttr("skip_final_snapshot"),
+		}
+
+		for _, zone := range res.GetAttr("availability_zones").AsStrings() {
+			cluster.AvailabilityZones = append(cluster.AvailabilityZones, types.String(zone, res.Metadata()))
+		}
+
+		for _, instanceRes := range res.FindAllBackRelated(
+			"aws_rds_cluster_instance", "cluster_identifier", "id", "cluster_identifier",
+		) {
+			instance := adaptRDSClusterInstance(g, instanceRes)
+			// the storage of the instances is encrypted by the cluster
+			instance.StorageEncrypted = cluster.Encryption.EncryptStorage
+			instance.Encryption = cluster.Encryption
+			if instance.PublicAccess.IsTrue() {
+				cluster.PublicAccess = instance.PublicAccess
+			}
+			cluster.Instances = append(cluster.Instances, instance)
+			adoptedInstances[instanceRes.ID()] = struct{}{}
+		}
+
+		clusters = append(clusters, cluster)
+	}
+
+	// instances of clusters that are not managed by the plan
+	for _, instanceRes := range g.FindResourcesByType("aws_rds_cluster_instance") {
+		if _, adopted := adoptedInstances[instanceRes.ID()]; adopted {
+			continue
+		}
+
+		// only the instance is managed by the plan, the settings of the cluster are not known
+		metadata := types.NewUnmanagedMetadata()
+		instance := adaptRDSClusterInstance(g, instanceRes)
+		clusters = append(clusters, rds.Cluster{
+			Metadata:                  metadata,
+			BackupRetentionPeriodDays: types.IntUnresolvable(metadata),
+			Encryption: rds.Encryption{
+				Metadata:       metadata,
+				EncryptStorage: types.BoolUnresolvable(metadata),
+				KMSKeyID:       types.StringUnresolvable(metadata),
+			},
+			PublicAccess:         instance.PublicAccess,
+			Engine:               instance.Engine,
+			Instances:            []rds.ClusterInstance{instance},
+			LatestRestorableTime: types.TimeUnresolvable(metadata),
+		})
+	}
+
+	return clusters
+}
+
+func adaptRDSClusterInstance(g *Graph, res *Node) rds.ClusterInstance {
+	return rds.ClusterInstance{
+		Instance:          adaptRDSInstance(g, res),
+		ClusterIdentifier: res.GetStringAttr("cluster_identifier"),
+	}
+}
+
+func adaptRDSClassic(g *Graph) rds.Classic {
+	var securityGroups []rds.DBSecurityGroup
+	for _, res := range g.FindResourcesByType("aws_db_security_group") {
+		securityGroups = append(securityGroups, rds.DBSecurityGroup{
+			Metadata: res.Metadata(),
+		})
+	}
+	return rds.Classic{
+		DBSecurityGroups: securityGroups,
+	}
+}
+
+func adaptRDSSnapshots(g *Graph) []rds.Snapshots {
+	var snapshots []rds.Snapshots
+	for _, res := range g.FindResourcesByType("aws_db_snapshot") {
+		snapshot := rds.Snapshots{
+			Metadata:             res.Metadata(),
+			DBSnapshotIdentifier: res.GetStringAttr("db_snapshot_identifier"),
+			DBSnapshotArn:        res.GetStringAttr("db_snapshot_arn"),
+			Encrypted:            res.GetBoolAttr("encrypted"),
+			KmsKeyId:             res.GetStringAttr("kms_key_id"),
+		}
+
+		// the encryption of the snapshot is inherited from the instance
+		if res.GetAttr("encrypted").IsNil() {
+			if instance := res.FindRelated(
+				"aws_db_instance", "db_instance_identifier", "identifier", "id",
+			); instance != nil {
+				snapshot.Encrypted = instance.GetBoolAttr("storage_encrypted")
+				snapshot.KmsKeyId = getKMSKeyID(g, instance, "kms_key_id")
+			}
+		}
+
+		if sharedAccounts := res.GetAttr("shared_accounts").AsStrings(); len(sharedAccounts) > 0 {
+			attribute := rds.DBSnapshotAttributes{
+				Metadata: res.Metadata(),
+			}
+			for _, account := range sharedAccounts {
+				attribute.AttributeValues = append(attribute.AttributeValues, types.String(account, res.Metadata()))
+			}
+			snapshot.SnapshotAttributes = append(snapshot.SnapshotAttributes, attribute)
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots
+}
+
+func adaptRDSParameterGroups(g *Graph) []rds.ParameterGroups {
+	var parameterGroups []rds.ParameterGroups
+	for _, groupType := range []string{"aws_db_parameter_group", "aws_rds_cluster_parameter_group"} {
+		for _, res := range g.FindResourcesByType(groupType) {
+			parameterGroup := rds.ParameterGroups{
+				Metadata:               res.Metadata(),
+				DBParameterGroupName:   res.GetStringAttr("name"),
+				DBParameterGroupFamily: res.GetStringAttr("family"),
+			}
+
+			for _, parameter := range res.GetAttr("parameter").ToList() {
+				parameterGroup.Parameters = append(parameterGroup.Parameters, rds.Parameters{
+					Metadata:       res.Metadata(),
+					ParameterName:  parameter.GetStringAttr("name"),
+					ParameterValue: parameter.GetStringAttr("value"),
+				})
+			}
+
+			parameterGroups = append(parameterGroups, parameterGroup)
+		}
+	}
+	return parameterGroups
+}
@@ -0,0 +1,155 @@
+package tfplanadapt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/kms"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/rds"
+	"github.com/aquasecurity/defsec/pkg/state"
+	"github.com/aquasecurity/defsec/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptRDS(t *testing.T) {
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			KMS: kms.KMS{
+				Keys: []kms.Key{
+					{
+						Usage:           types.String("ENCRYPT_DECRYPT", types.Metadata{}),
+						RotationEnabled: types.Bool(true, types.Metadata{}),
+					},
+				},
+			},
+			RDS: rds.RDS{
+				Instances: []rds.Instance{
+					{
+						BackupRetentionPeriodDays: types.Int(7, types.Metadata{}),
+						PerformanceInsights: rds.PerformanceInsights{
+							Enabled:  types.Bool(true, types.Metadata{}),
+							KMSKeyID: types.StringUnresolvable(types.Metadata{}),
+						},
+						Encryption: rds.Encryption{
+							EncryptStorage: types.Bool(true, types.Metadata{}),
+							KMSKeyID:       types.StringUnresolvable(types.Metadata{}),
+						},
+						PublicAccess:         types.Bool(false, types.Metadata{}),
+						Engine:               types.String("postgres", types.Metadata{}),
+						IAMAuthEnabled:       types.Bool(true, types.Metadata{}),
+						DeletionProtection:   types.Bool(true, types.Metadata{}),
+						StorageEncrypted:     types.Bool(true, types.Metadata{}),
+						DBInstanceIdentifier: types.String("main", types.Metadata{}),
+						DBParameterGroups: []rds.DBParameterGroupsList{
+							{
+								DBParameterGroupName: types.String("postgres", types.Metadata{}),
+							},
+						},
+						EnabledCloudwatchLogsExports: []types.StringValue{
+							types.String("postgresql", types.Metadata{}),
+						},
+						EngineVersion:           types.String("15.4", types.Metadata{}),
+						AutoMinorVersionUpgrade: types.Bool(true, types.Metadata{}),
+						MultiAZ:                 types.Bool(true, types.Metadata{}),
+						PubliclyAccessible:      types.Bool(false, types.Metadata{}),
+						ReadReplicaDBInstanceIdentifiers: []types.StringValue{
+							types.String("replica", types.Metadata{}),
+						},
+					},
+					{
+						ReplicationSourceARN:    types.String("main", types.Metadata{}),
+						PublicAccess:            types.Bool(true, types.Metadata{}),
+						DBInstanceIdentifier:    types.String("replica", types.Metadata{}),
+						AutoMinorVersionUpgrade: types.Bool(true, types.Metadata{}),
+						PubliclyAccessible:      types.Bool(true, types.Metadata{}),
+					},
+				},
+				Clusters: []rds.Cluster{
+					{
+						BackupRetentionPeriodDays: types.Int(1, types.Metadata{}),
+						Encryption: rds.Encryption{
+							EncryptStorage: types.Bool(true, types.Metadata{}),
+						},
+						PublicAccess: types.Bool(true, types.Metadata{}),
+						Engine:       types.String("aurora-postgresql", types.Metadata{}),
+						AvailabilityZones: []types.StringValue{
+							types.String("us-east-1a", types.Metadata{}),
+						},
+						SkipFinalSnapshot: types.Bool(true, types.Metadata{}),
+						Instances: []rds.ClusterInstance{
+							{
+								Instance: rds.Instance{
+									Encryption: rds.Encryption{
+										EncryptStorage: types.Bool(true, types.Metadata{}),
+									},
+									PublicAccess:            types.Bool(true, types.Metadata{}),
+									Engine:                  types.String("aurora-postgresql", types.Metadata{}),
+									StorageEncrypted:        types.Bool(true, types.Metadata{}),
+									DBInstanceIdentifier:    types.String("aurora-1", types.Metadata{}),
+									AutoMinorVersionUpgrade: types.Bool(true, types.Metadata{}),
+									PubliclyAccessible:      types.Bool(true, types.Metadata{}),
+								},
+							},
+						},
+					},
+					{
+						BackupRetentionPeriodDays: types.IntUnresolvable(types.Metadata{}),
+						Encryption: rds.Encryption{
+							EncryptStorage: types.BoolUnresolvable(types.Metadata{}),
+							KMSKeyID:       types.StringUnresolvable(types.Metadata{}),
+						},
+						Engine: types.String("aurora-mysql", types.Metadata{}),
+						Instances: []rds.ClusterInstance{
+							{
+								Instance: rds.Instance{
+									Engine:                  types.String("aurora-mysql", types.Metadata{}),
+									DBInstanceIdentifier:    types.String("external-1", types.Metadata{}),
+									AutoMinorVersionUpgrade: types.Bool(true, types.Metadata{}),
+								},
+								ClusterIdentifier: types.String("external", types.Metadata{}),
+							},
+						},
+					},
+				},
+				Classic: rds.Classic{
+					DBSecurityGroups: []rds.DBSecurityGroup{{}},
+				},
+				Snapshots: []rds.Snapshots{
+					{
+						DBSnapshotIdentifier: types.String("main-snapshot", types.Metadata{}),
+						Encrypted:            types.Bool(true, types.Metadata{}),
+						KmsKeyId:             types.StringUnresolvable(types.Metadata{}),
+					},
+				},
+				ParameterGroups: []rds.ParameterGroups{
+					{
+						DBParameterGroupName:   types.String("postgres", types.Metadata{}),
+						DBParameterGroupFamily: types.String("postgres15", types.Metadata{}),
+						Parameters: []rds.Parameters{
+							{
+								ParameterName:  types.String("rds.force_ssl", types.Metadata{}),
+								ParameterValue: types.String("1", types.Metadata{}),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	got := runAdaptTest(t, filepath.Join("testdata", "rds", "tfplan.json"), expected)
+
+	// the cluster of the external instance is not managed by the plan
+	external := got.AWS.RDS.Clusters[1]
+	assert.True(t, external.Metadata.IsUnmanaged())
+	assert.Equal(t, "aws_rds_cluster_instance.external", external.Instances[0].Metadata.Reference())
+	for _, result := range Scan(got) {
+		switch result.Rule().AVDID {
+		case "AVD-AWS-0077", "AVD-AWS-0079":
+			assert.NotEqual(t, "aws_rds_cluster_instance.external", result.Metadata().Reference())
+		}
+	}
+}
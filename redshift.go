@@ -0,0 +1,108 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/redshift"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptRedshift(g *Graph) redshift.Redshift {
+	return redshift.Redshift{
+		Clusters:          adaptRedshiftClusters(g),
+		ClusterParameters: adaptRedshiftClusterParameters(g),
+		SecurityGroups:    adaptRedshiftSecurityGroups(g),
+	}
+}
+
+func adaptRedshiftClusters(g *Graph) []redshift.Cluster {
+	var clusters []redshift.Cluster
+	for _, res := range g.FindResourcesByType("aws_redshift_cluster") {
+		cluster := redshift.Cluster{
+			Metadata:                         res.Metadata(),
+			ClusterIdentifier:                res.GetStringAttr("cluster_identifier"),
+			NodeType:                         res.GetStringAttr("node_type"),
+			VpcId:                            types.StringDefault("", res.Metadata()),
+			NumberOfNodes:                    res.GetIntAttr("number_of_nodes", 1),
+			PubliclyAccessible:               res.GetBoolAttr("publicly_accessible"),
+			AllowVersionUpgrade:              res.GetBoolAttr("allow_version_upgrade", true),
+			MasterUsername:                   res.GetStringAttr("master_username"),
+			AutomatedSnapshotRetentionPeriod: res.GetIntAttr("automated_snapshot_retention_period", 1),
+			LoggingEnabled:                   adaptRedshiftLogging(res),
+			EndPoint: redshift.EndPoint{
+				Metadata: res.Metadata(),
+				Port:     res.GetIntAttr("port", 5439),
+			},
+			Encryption: redshift.Encryption{
+				Metadata: res.Metadata(),
+				Enabled:  res.GetBoolAttr("encrypted"),
+				KMSKeyID: getKMSKeyID(g, res, "kms_key_id"),
+			},
+			SubnetGroupName: res.GetStringAttr("cluster_subnet_group_name"),
+		}
+
+		if subnetGroup := res.FindRelated(
+			"aws_redshift_subnet_group", "cluster_subnet_group_name", "name", "id",
+		); subnetGroup != nil {
+			if cluster.SubnetGroupName.IsEmpty() {
+				cluster.SubnetGroupName = types.String(subnetGroup.GetStringAttr("name").Value(), res.Metadata())
+			}
+			if subnet := subnetGroup.FindRelated("aws_subnet", "subnet_ids", "id"); subnet != nil {
+				cluster.VpcId = adaptSubnetVpcID(subnet, res.Metadata())
+			}
+		}
+
+		clusters = append(clusters, cluster)
+	}
+	return clusters
+}
+
+// adaptSubnetVpcID returns the VPC of the subnet, the VPC created by the plan is not known until apply
+func adaptSubnetVpcID(subnet *Node, metadata types.Metadata) types.StringValue {
+	if vpcID := subnet.GetStringAttr("vpc_id"); vpcID.IsNotEmpty() {
+		return types.String(vpcID.Value(), metadata)
+	}
+	if subnet.hasReference("vpc_id") {
+		return types.StringUnresolvable(metadata)
+	}
+	return types.StringDefault("", metadata)
+}
+
+// adaptRedshiftLogging reports whether the audit logging is enabled by the deprecated logging block
+// or by the separate logging resource
+func adaptRedshiftLogging(res *Node) types.BoolValue {
+	if res.GetAttr("logging").GetBoolAttr("enable").IsTrue() {
+		return types.Bool(true, res.Metadata())
+	}
+
+	if logging := res.FindBackRelated(
+		"aws_redshift_logging", "cluster_identifier", "cluster_identifier", "id",
+	); logging != nil {
+		return types.Bool(true, logging.Metadata())
+	}
+
+	return types.BoolDefault(false, res.Metadata())
+}
+
+func adaptRedshiftClusterParameters(g *Graph) []redshift.ClusterParameter {
+	var parameters []redshift.ClusterParameter
+	for _, res := range g.FindResourcesByType("aws_redshift_parameter_group") {
+		for _, parameter := range res.GetAttr("parameter").ToList() {
+			parameters = append(parameters, redshift.ClusterParameter{
+				Metadata:       parameter.Metadata(),
+				ParameterName:  parameter.GetStringAttr("name"),
+				ParameterValue: parameter.GetStringAttr("value"),
+			})
+		}
+	}
+	return parameters
+}
+
+func adaptRedshiftSecurityGroups(g *Graph) []redshift.SecurityGroup {
+	var groups []redshift.SecurityGroup
+	for _, res := range g.FindResourcesByType("aws_redshift_security_group") {
+		groups = append(groups, redshift.SecurityGroup{
+			Metadata:    res.Metadata(),
+			Description: res.GetStringAttr("description"),
+		})
+	}
+	return groups
+}
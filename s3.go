@@ -3,6 +3,7 @@ package tfplanadapt
 import (
 	"sort"
 
+	"github.com/aquasecurity/defsec/pkg/providers/aws/iam"
 	"github.com/aquasecurity/defsec/pkg/providers/aws/s3"
 	"github.com/aquasecurity/defsec/pkg/types"
 )
@@ -11,19 +12,30 @@ func adaptS3(g *Graph) s3.S3 {
 	var buckets []s3.Bucket
 	for _, res := range g.FindResourcesByType("aws_s3_bucket") {
 		bucket := s3.Bucket{
+			Metadata:       res.Metadata(),
 			Name:           res.GetStringAttr("bucket", res.ID()),
-			BucketLocation: res.GetStringAttr("region"),
+			BucketLocation: types.StringDefault(res.region(), res.Metadata()),
+			Encryption: s3.Encryption{
+				Metadata:  res.Metadata(),
+				Enabled:   types.BoolDefault(false, res.Metadata()),
+				Algorithm: types.StringDefault("", res.Metadata()),
+				KMSKeyId:  types.StringDefault("", res.Metadata()),
+			},
+			Logging: s3.Logging{
+				Metadata:     res.Metadata(),
+				Enabled:      types.BoolDefault(false, res.Metadata()),
+				TargetBucket: types.StringDefault("", res.Metadata()),
+			},
 		}
 
 		adaptVersioning(&bucket, res)
 		adaptLogging(&bucket, res)
-		adaptSSE(&bucket, res)
-		adaptAccessBlock(&bucket, res)
+		adaptSSE(g, &bucket, res)
+		adaptAccessBlock(&bucket, res, findAccountAccessBlock(g, res))
 		adaptLifecycleConfiguration(&bucket, res)
-
-		if bucketAcl := res.FindBackRelated("aws_s3_bucket_acl", "bucket", "bucket", "id"); bucketAcl != nil {
-			bucket.ACL = bucketAcl.GetStringAttr("acl")
-		}
+		adaptBucketPolicies(&bucket, res)
+		adaptACL(&bucket, res)
+		adaptObjects(&bucket, res)
 
 		if accelerateConfiguration := res.FindBackRelated(
 			"aws_s3_bucket_accelerate_configuration", "bucket", "bucket", "id",
@@ -46,6 +58,7 @@ func adaptVersioning(bucket *s3.Bucket, res *Node) {
 	versioningAttr := res.GetAttr("versioning")
 
 	bucket.Versioning = s3.Versioning{
+		Metadata:  res.Metadata(),
 		Enabled:   versioningAttr.GetBoolAttr("enabled"),
 		MFADelete: versioningAttr.GetBoolAttr("mfa_delete"),
 	}
@@ -56,8 +69,9 @@ func adaptVersioning(bucket *s3.Bucket, res *Node) {
 		versioningConf := bucketVersioning.GetAttr("versioning_configuration")
 		if !versioningConf.IsNil() {
 			bucket.Versioning = s3.Versioning{
-				Enabled:   types.Bool(versioningConf.GetStringAttr("status").EqualTo("Enabled"), types.Metadata{}),
-				MFADelete: types.Bool(versioningConf.GetStringAttr("mfa_delete").EqualTo("Enabled"), types.Metadata{}),
+				Metadata:  bucketVersioning.Metadata(),
+				Enabled:   types.Bool(versioningConf.GetStringAttr("status").EqualTo("Enabled"), bucketVersioning.Metadata()),
+				MFADelete: types.Bool(versioningConf.GetStringAttr("mfa_delete").EqualTo("Enabled"), bucketVersioning.Metadata()),
 			}
 		}
 	}
@@ -71,77 +85,214 @@ func adaptLogging(bucket *s3.Bucket, res *Node) {
 			"aws_s3_bucket", "target_bucket", "bucket", "id",
 		); logBucket != nil {
 			bucket.Logging = s3.Logging{
-				Enabled:      types.Bool(true, types.Metadata{}),
+				Metadata:     bucketLoggingRes.Metadata(),
+				Enabled:      types.Bool(true, bucketLoggingRes.Metadata()),
 				TargetBucket: logBucket.GetStringAttr("bucket", logBucket.ID()),
 			}
 		}
 	}
 }
 
-func adaptSSE(bucket *s3.Bucket, res *Node) {
+func adaptSSE(g *Graph, bucket *s3.Bucket, res *Node) {
 	// legacy atribute
 	applySSE := res.GetNestedAttr("server_side_encryption_configuration.rule.apply_server_side_encryption_by_default")
 	if !applySSE.IsNil() {
 		kmsKeyIdField := "server_side_encryption_configuration.rule.apply_server_side_encryption_by_default.kms_master_key_id"
-		bucket.Encryption = getEncryption(applySSE, res, kmsKeyIdField)
+		bucket.Encryption = getEncryption(g, applySSE, res, kmsKeyIdField)
 	} else if sse := res.FindBackRelated(
 		"aws_s3_bucket_server_side_encryption_configuration", "bucket", "bucket", "id",
 	); sse != nil {
 		if applySSE := sse.GetNestedAttr("rule.apply_server_side_encryption_by_default"); !applySSE.IsNil() {
 			kmsKeyIdField := "rule.apply_server_side_encryption_by_default.kms_master_key_id"
-			bucket.Encryption = getEncryption(applySSE, sse, kmsKeyIdField)
+			bucket.Encryption = getEncryption(g, applySSE, sse, kmsKeyIdField)
 		}
 	}
 }
 
-func getEncryption(attr *Attribute, to *Node, field string) s3.Encryption {
+func getEncryption(g *Graph, attr *Attribute, to *Node, field string) s3.Encryption {
 	algorithm := attr.GetStringAttr("sse_algorithm")
-	enabled := types.BoolDefault(false, types.Metadata{})
+	enabled := types.BoolDefault(false, attr.Metadata())
 	if algorithm.IsNotEmpty() {
-		enabled = types.Bool(true, types.Metadata{})
+		enabled = types.Bool(true, attr.Metadata())
 	}
 
-	kmsKeyID := attr.GetStringAttr("kms_master_key_id")
-	if kmsKeyID.IsEmpty() {
-		if kmsKeyResource := to.FindRelated("aws_kms_key", field, "kms_key_id", "arn"); kmsKeyResource != nil {
-			// mock ARN
-			kmsKeyID = types.String("1234abcd-12ab-34cd-56ef-1234567890ab", types.Metadata{}) // TODO
-		}
-	}
+	kmsKeyID := resolveKMSKeyAttr(g, to, attr.GetStringAttr("kms_master_key_id"), field)
 
 	return s3.Encryption{
+		Metadata:  attr.Metadata(),
 		Enabled:   enabled,
 		Algorithm: algorithm,
 		KMSKeyId:  kmsKeyID,
 	}
 }
 
-func adaptAccessBlock(bucket *s3.Bucket, res *Node) {
-	if accessBlock := res.FindBackRelated(
+// findAccountAccessBlock returns the account-level public access block of the account the bucket belongs to
+func findAccountAccessBlock(g *Graph, bucket *Node) *Node {
+	for _, accessBlock := range g.FindResourcesByType("aws_s3_account_public_access_block") {
+		if accountID := accessBlock.GetStringAttr("account_id"); accountID.IsNotEmpty() && bucket.accountID() != "" {
+			if accountID.EqualTo(bucket.accountID()) {
+				return accessBlock
+			}
+			continue
+		}
+		if accessBlock.sameAccount(bucket) {
+			return accessBlock
+		}
+	}
+	return nil
+}
+
+func adaptAccessBlock(bucket *s3.Bucket, res *Node, accountAccessBlock *Node) {
+	accessBlock := res.FindBackRelated(
 		"aws_s3_bucket_public_access_block", "bucket", "bucket", "id",
-	); accessBlock != nil {
-		bucket.PublicAccessBlock = &s3.PublicAccessBlock{
-			BlockPublicACLs:       accessBlock.GetBoolAttr("block_public_acls"),
-			BlockPublicPolicy:     accessBlock.GetBoolAttr("block_public_policy"),
-			IgnorePublicACLs:      accessBlock.GetBoolAttr("ignore_public_acls"),
-			RestrictPublicBuckets: accessBlock.GetBoolAttr("restrict_public_buckets"),
+	)
+	if accessBlock == nil && accountAccessBlock == nil {
+		return
+	}
+
+	metadataSource := accessBlock
+	if metadataSource == nil {
+		metadataSource = accountAccessBlock
+	}
+
+	// the most restrictive combination of the bucket and account settings is applied
+	bucket.PublicAccessBlock = &s3.PublicAccessBlock{
+		Metadata:              metadataSource.Metadata(),
+		BlockPublicACLs:       effectiveAccessBlockSetting("block_public_acls", accessBlock, accountAccessBlock),
+		BlockPublicPolicy:     effectiveAccessBlockSetting("block_public_policy", accessBlock, accountAccessBlock),
+		IgnorePublicACLs:      effectiveAccessBlockSetting("ignore_public_acls", accessBlock, accountAccessBlock),
+		RestrictPublicBuckets: effectiveAccessBlockSetting("restrict_public_buckets", accessBlock, accountAccessBlock),
+	}
+}
+
+func effectiveAccessBlockSetting(name string, accessBlocks ...*Node) types.BoolValue {
+	var setting types.BoolValue
+	for _, accessBlock := range accessBlocks {
+		if accessBlock == nil {
+			continue
+		}
+		if setting = accessBlock.GetBoolAttr(name); setting.IsTrue() {
+			return setting
+		}
+	}
+	return setting
+}
+
+func adaptObjects(bucket *s3.Bucket, res *Node) {
+	for _, objectType := range []string{"aws_s3_object", "aws_s3_bucket_object"} {
+		for _, object := range res.FindAllBackRelated(objectType, "bucket", "bucket", "id") {
+			bucket.Objects = append(bucket.Objects, s3.Contents{
+				Metadata: object.Metadata(),
+			})
 		}
 	}
 }
 
 func adaptLifecycleConfiguration(bucket *s3.Bucket, res *Node) {
-	if lifecycleCfg := res.FindBackRelated(
-		"aws_s3_bucket_lifecycle_configuration", "bucket", "bucket", "id",
-	); lifecycleCfg != nil {
-		var rules []s3.Rules
-		for _, rule := range lifecycleCfg.GetAttr("rule").ToList() {
-			rules = append(rules, s3.Rules{
-				Status: rule.GetStringAttr("status"),
+	var rules []s3.Rules
+	for _, rule := range adaptLifecycleRules(res) {
+		rules = append(rules, s3.Rules{
+			Metadata: rule.Metadata,
+			Status:   rule.Status,
+		})
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Status.Value() < rules[j].Status.Value()
+	})
+	bucket.LifecycleConfiguration = rules
+}
+
+const (
+	allUsersGroup           = "http://acs.amazonaws.com/groups/global/AllUsers"
+	authenticatedUsersGroup = "http://acs.amazonaws.com/groups/global/AuthenticatedUsers"
+)
+
+func adaptACL(bucket *s3.Bucket, res *Node) {
+	if ownershipControls := res.FindBackRelated(
+		"aws_s3_bucket_ownership_controls", "bucket", "bucket", "id",
+	); ownershipControls != nil &&
+		ownershipControls.GetAttr("rule").GetStringAttr("object_ownership").EqualTo("BucketOwnerEnforced") {
+		// ACLs are disabled and no longer affect access to the bucket
+		bucket.ACL = types.String("private", ownershipControls.Metadata())
+		return
+	}
+
+	if bucketAcl := res.FindBackRelated("aws_s3_bucket_acl", "bucket", "bucket", "id"); bucketAcl != nil {
+		bucket.ACL = bucketAcl.GetStringAttr("acl")
+		if bucket.ACL.IsEmpty() {
+			var grants []aclGrant
+			for _, grant := range bucketAcl.GetNestedAttr("access_control_policy.grant").ToList() {
+				grants = append(grants, aclGrant{
+					uri:         grant.GetStringAttr("grantee.uri").Value(),
+					permissions: []string{grant.GetStringAttr("permission").Value()},
+				})
+			}
+			if len(grants) > 0 {
+				bucket.ACL = types.String(cannedACLFromGrants(grants), bucketAcl.Metadata())
+			}
+		}
+		return
+	}
+
+	// legacy attributes
+	bucket.ACL = res.GetStringAttr("acl")
+	if bucket.ACL.IsEmpty() {
+		var grants []aclGrant
+		for _, grant := range res.GetAttr("grant").ToList() {
+			grants = append(grants, aclGrant{
+				uri:         grant.GetStringAttr("uri").Value(),
+				permissions: grant.GetNestedAttr("permissions").AsStrings(),
 			})
 		}
-		sort.Slice(rules, func(i, j int) bool {
-			return rules[i].Status.Value() < rules[j].Status.Value()
+		if len(grants) > 0 {
+			bucket.ACL = types.String(cannedACLFromGrants(grants), res.Metadata())
+		}
+	}
+}
+
+type aclGrant struct {
+	uri         string
+	permissions []string
+}
+
+// cannedACLFromGrants returns the canned ACL that gives the same public access as the grants
+func cannedACLFromGrants(grants []aclGrant) string {
+	acl := "private"
+	for _, grant := range grants {
+		for _, permission := range grant.permissions {
+			switch grant.uri {
+			case allUsersGroup:
+				switch permission {
+				case "WRITE", "WRITE_ACP", "FULL_CONTROL":
+					return "public-read-write"
+				case "READ", "READ_ACP":
+					acl = "public-read"
+				}
+			case authenticatedUsersGroup:
+				if acl == "private" {
+					acl = "authenticated-read"
+				}
+			}
+		}
+	}
+	return acl
+}
+
+func adaptBucketPolicies(bucket *s3.Bucket, res *Node) {
+	// the legacy attribute is ignored if the bucket has a separate policy resource
+	policyRes := res
+	if bucketPolicy := res.FindBackRelated(
+		"aws_s3_bucket_policy", "bucket", "bucket", "id",
+	); bucketPolicy != nil {
+		policyRes = bucketPolicy
+	}
+
+	if document, ok := adaptPolicyDocument(policyRes, "policy"); ok {
+		bucket.BucketPolicies = append(bucket.BucketPolicies, iam.Policy{
+			Metadata: policyRes.Metadata(),
+			Name:     types.StringDefault("", policyRes.Metadata()),
+			Document: document,
+			Builtin:  types.BoolDefault(false, policyRes.Metadata()),
 		})
-		bucket.LifecycleConfiguration = rules
 	}
 }
@@ -0,0 +1,261 @@
+package tfplanadapt
+
+import (
+	"sort"
+
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+// BucketExtension contains the bucket configuration that is not covered by s3.Bucket
+type BucketExtension struct {
+	Metadata       types.Metadata
+	Name           types.StringValue
+	LifecycleRules []LifecycleRule
+	Replication    *Replication
+	ObjectLock     *ObjectLock
+}
+
+// LifecycleRule is the rule of the bucket lifecycle configuration. The rule applies to the objects
+// that match all the conditions of the filter: the key prefix, the tags and the object size
+type LifecycleRule struct {
+	Metadata                           types.Metadata
+	ID                                 types.StringValue
+	Status                             types.StringValue
+	Prefix                             types.StringValue
+	Tags                               types.MapValue
+	ObjectSizeGreaterThan              types.IntValue
+	ObjectSizeLessThan                 types.IntValue
+	ExpirationDays                     types.IntValue
+	ExpiredObjectDeleteMarker          types.BoolValue
+	NoncurrentVersionExpirationDays    types.IntValue
+	AbortIncompleteMultipartUploadDays types.IntValue
+}
+
+// Replication is the replication configuration of the bucket
+type Replication struct {
+	Metadata types.Metadata
+	Role     types.StringValue
+	Rules    []ReplicationRule
+}
+
+// ReplicationRule is the rule of the bucket replication configuration. The rule replicates the objects
+// that match all the conditions of the filter: the key prefix and the tags
+type ReplicationRule struct {
+	Metadata          types.Metadata
+	ID                types.StringValue
+	Status            types.StringValue
+	Prefix            types.StringValue
+	Tags              types.MapValue
+	DestinationBucket types.StringValue
+	StorageClass      types.StringValue
+	ReplicaKMSKeyID   types.StringValue
+}
+
+// ObjectLock is the object lock configuration of the bucket with the default retention of the objects
+type ObjectLock struct {
+	Metadata       types.Metadata
+	Enabled        types.BoolValue
+	Mode           types.StringValue
+	RetentionDays  types.IntValue
+	RetentionYears types.IntValue
+}
+
+// AdaptS3Extensions adapts the bucket configuration that is not covered by the defsec state
+func AdaptS3Extensions(g *Graph) []BucketExtension {
+	var buckets []BucketExtension
+	for _, res := range g.FindResourcesByType("aws_s3_bucket") {
+		buckets = append(buckets, BucketExtension{
+			Metadata:       res.Metadata(),
+			Name:           res.GetStringAttr("bucket", res.ID()),
+			LifecycleRules: adaptLifecycleRules(res),
+			Replication:    adaptReplication(g, res),
+			ObjectLock:     adaptObjectLock(res),
+		})
+	}
+
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].Name.Value() < buckets[j].Name.Value()
+	})
+	return buckets
+}
+
+func adaptLifecycleRules(res *Node) []LifecycleRule {
+	var rules []LifecycleRule
+	if lifecycleCfg := res.FindBackRelated(
+		"aws_s3_bucket_lifecycle_configuration", "bucket", "bucket", "id",
+	); lifecycleCfg != nil {
+		for _, rule := range lifecycleCfg.GetAttr("rule").ToList() {
+			filter := adaptRuleFilter(rule)
+			rules = append(rules, LifecycleRule{
+				Metadata:                           lifecycleCfg.Metadata(),
+				ID:                                 rule.GetStringAttr("id"),
+				Status:                             rule.GetStringAttr("status"),
+				Prefix:                             filter.prefix,
+				Tags:                               filter.tags,
+				ObjectSizeGreaterThan:              filter.objectSizeGreaterThan,
+				ObjectSizeLessThan:                 filter.objectSizeLessThan,
+				ExpirationDays:                     rule.GetIntAttr("expiration.days"),
+				ExpiredObjectDeleteMarker:          rule.GetBoolAttr("expiration.expired_object_delete_marker"),
+				NoncurrentVersionExpirationDays:    rule.GetIntAttr("noncurrent_version_expiration.noncurrent_days"),
+				AbortIncompleteMultipartUploadDays: rule.GetIntAttr("abort_incomplete_multipart_upload.days_after_initiation"),
+			})
+		}
+		return rules
+	}
+
+	// legacy attribute
+	for _, rule := range res.GetAttr("lifecycle_rule").ToList() {
+		status := "Disabled"
+		if rule.GetBoolAttr("enabled").IsTrue() {
+			status = "Enabled"
+		}
+
+		rules = append(rules, LifecycleRule{
+			Metadata:                           res.Metadata(),
+			ID:                                 rule.GetStringAttr("id"),
+			Status:                             types.String(status, res.Metadata()),
+			Prefix:                             rule.GetStringAttr("prefix"),
+			Tags:                               types.Map(rule.GetNestedAttr("tags").AsStringMap(), res.Metadata()),
+			ObjectSizeGreaterThan:              types.IntDefault(0, res.Metadata()),
+			ObjectSizeLessThan:                 types.IntDefault(0, res.Metadata()),
+			ExpirationDays:                     rule.GetIntAttr("expiration.days"),
+			ExpiredObjectDeleteMarker:          rule.GetBoolAttr("expiration.expired_object_delete_marker"),
+			NoncurrentVersionExpirationDays:    rule.GetIntAttr("noncurrent_version_expiration.days"),
+			AbortIncompleteMultipartUploadDays: rule.GetIntAttr("abort_incomplete_multipart_upload_days"),
+		})
+	}
+	return rules
+}
+
+// ruleFilter contains the conditions of the lifecycle or replication rule filter
+type ruleFilter struct {
+	prefix                types.StringValue
+	tags                  types.MapValue
+	objectSizeGreaterThan types.IntValue
+	objectSizeLessThan    types.IntValue
+}
+
+// adaptRuleFilter adapts the filter of the rule. The filter contains a single condition
+// or the and block if several conditions are combined, the deprecated prefix of the rule
+// is used if the filter has no prefix
+func adaptRuleFilter(rule *Attribute) ruleFilter {
+	filter := rule.GetNestedAttr("filter")
+
+	var tags map[string]string
+	if and := filter.GetNestedAttr("and"); len(and.ToList()) > 0 {
+		filter = and
+		tags = and.GetNestedAttr("tags").AsStringMap()
+	} else if tag := filter.GetNestedAttr("tag"); len(tag.ToList()) > 0 {
+		tags = map[string]string{
+			tag.GetStringAttr("key").Value(): tag.GetStringAttr("value").Value(),
+		}
+	}
+
+	prefix := filter.GetStringAttr("prefix")
+	if prefix.IsEmpty() {
+		prefix = rule.GetStringAttr("prefix")
+	}
+
+	return ruleFilter{
+		prefix:                prefix,
+		tags:                  types.Map(tags, rule.Metadata()),
+		objectSizeGreaterThan: filter.GetIntAttr("object_size_greater_than"),
+		objectSizeLessThan:    filter.GetIntAttr("object_size_less_than"),
+	}
+}
+
+func adaptReplication(g *Graph, res *Node) *Replication {
+	replicationCfg := res.FindBackRelated(
+		"aws_s3_bucket_replication_configuration", "bucket", "bucket", "id",
+	)
+	if replicationCfg == nil {
+		return nil
+	}
+
+	targetBuckets := replicationCfg.FindAllRelated("aws_s3_bucket", "rule.destination.bucket", "arn")
+
+	var rules []ReplicationRule
+	for _, rule := range replicationCfg.GetAttr("rule").ToList() {
+		filter := adaptRuleFilter(rule)
+
+		destination := rule.GetNestedAttr("destination")
+		destinationBucket := destination.GetStringAttr("bucket")
+		if targetBucket := findTargetBucket(targetBuckets, destinationBucket); targetBucket != nil {
+			destinationBucket = targetBucket.GetStringAttr("bucket", targetBucket.ID())
+		}
+
+		replicaKMSKeyID := resolveKMSKeyAttr(
+			g, replicationCfg, destination.GetStringAttr("encryption_configuration.replica_kms_key_id"),
+			"rule.destination.encryption_configuration.replica_kms_key_id",
+		)
+
+		rules = append(rules, ReplicationRule{
+			Metadata:          replicationCfg.Metadata(),
+			ID:                rule.GetStringAttr("id"),
+			Status:            rule.GetStringAttr("status"),
+			Prefix:            filter.prefix,
+			Tags:              filter.tags,
+			DestinationBucket: destinationBucket,
+			StorageClass:      destination.GetStringAttr("storage_class"),
+			ReplicaKMSKeyID:   replicaKMSKeyID,
+		})
+	}
+
+	return &Replication{
+		Metadata: replicationCfg.Metadata(),
+		Role:     replicationCfg.GetStringAttr("role"),
+		Rules:    rules,
+	}
+}
+
+// findTargetBucket finds the bucket by ARN among the related buckets.
+// The ARN of the bucket created by the plan is not known, so the only related bucket is used
+func findTargetBucket(buckets []*Node, arn types.StringValue) *Node {
+	if arn.IsEmpty() {
+		if len(buckets) == 1 {
+			return buckets[0]
+		}
+		return nil
+	}
+
+	for _, bucket := range buckets {
+		if bucket.GetStringAttr("arn").EqualTo(arn.Value()) {
+			return bucket
+		}
+	}
+	return nil
+}
+
+func adaptObjectLock(res *Node) *ObjectLock {
+	if objectLockCfg := res.FindBackRelated(
+		"aws_s3_bucket_object_lock_configuration", "bucket", "bucket", "id",
+	); objectLockCfg != nil {
+		retention := objectLockCfg.GetNestedAttr("rule.default_retention")
+		return &ObjectLock{
+			Metadata:       objectLockCfg.Metadata(),
+			Enabled:        types.Bool(objectLockCfg.GetStringAttr("object_lock_enabled", "Enabled").EqualTo("Enabled"), objectLockCfg.Metadata()),
+			Mode:           retention.GetStringAttr("mode"),
+			RetentionDays:  retention.GetIntAttr("days"),
+			RetentionYears: retention.GetIntAttr("years"),
+		}
+	}
+
+	// legacy attributes
+	objectLockCfg := res.GetAttr("object_lock_configuration")
+	if res.GetBoolAttr("object_lock_enabled").IsFalse() && len(objectLockCfg.ToList()) == 0 {
+		return nil
+	}
+
+	retention := objectLockCfg.GetNestedAttr("rule.default_retention")
+	return &ObjectLock{
+		Metadata: res.Metadata(),
+		Enabled: types.Bool(
+			res.GetBoolAttr("object_lock_enabled").IsTrue() ||
+				objectLockCfg.GetStringAttr("object_lock_enabled").EqualTo("Enabled"),
+			res.Metadata(),
+		),
+		Mode:           retention.GetStringAttr("mode"),
+		RetentionDays:  retention.GetIntAttr("days"),
+		RetentionYears: retention.GetIntAttr("years"),
+	}
+}
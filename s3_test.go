@@ -5,15 +5,19 @@ import (
 	"testing"
 
 	"github.com/aquasecurity/defsec/pkg/providers/aws"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/iam"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/kms"
 	"github.com/aquasecurity/defsec/pkg/providers/aws/s3"
 	"github.com/aquasecurity/defsec/pkg/state"
 	"github.com/aquasecurity/defsec/pkg/types"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAdaptS3(t *testing.T) {
 
 	expected := &state.State{
 		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
 			S3: s3.S3{
 				Buckets: []s3.Bucket{
 					{
@@ -39,7 +43,7 @@ func TestAdaptS3(t *testing.T) {
 						Encryption: s3.Encryption{
 							Enabled:   types.Bool(true, types.Metadata{}),
 							Algorithm: types.String("aws:kms", types.Metadata{}),
-							KMSKeyId:  types.String("1234abcd-12ab-34cd-56ef-1234567890ab", types.Metadata{}),
+							KMSKeyId:  types.StringUnresolvable(types.Metadata{}),
 						},
 						Logging: s3.Logging{
 							Enabled:      types.Bool(true, types.Metadata{}),
@@ -64,8 +68,240 @@ func TestAdaptS3(t *testing.T) {
 					},
 				},
 			},
+			KMS: kms.KMS{
+				Keys: []kms.Key{
+					{
+						Usage:           types.String("ENCRYPT_DECRYPT", types.Metadata{}),
+						RotationEnabled: types.Bool(false, types.Metadata{}),
+					},
+				},
+			},
+		},
+	}
+
+	got := runAdaptTest(t, filepath.Join("testdata", "s3", "tfplan.json"), expected)
+
+	// the ARN of the key is known only after apply
+	assert.False(t, got.AWS.S3.Buckets[1].Encryption.KMSKeyId.GetMetadata().IsResolvable())
+}
+
+func TestAdaptS3BucketPolicies(t *testing.T) {
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			S3: s3.S3{
+				Buckets: []s3.Bucket{
+					{
+						Name: types.String("legacy", types.Metadata{}),
+						BucketPolicies: []iam.Policy{
+							{
+								Document: iam.Document{
+									Parsed: mustParsePolicy(t, `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Allow",
+      "Principal": "*",
+      "Action": "s3:GetObject",
+      "Resource": "arn:aws:s3:::legacy/*"
+    }
+  ]
+}`),
+								},
+							},
+						},
+					},
+					{
+						Name: types.String("test", types.Metadata{}),
+						BucketPolicies: []iam.Policy{
+							{
+								Document: iam.Document{
+									Parsed: mustParsePolicy(t, `{
+  "Version": "2012-10-17",
+  "Statement": [
+    {
+      "Effect": "Deny",
+      "Principal": "*",
+      "Action": "s3:*",
+      "Resource": "arn:aws:s3:::test/*",
+      "Condition": {
+        "Bool": {
+          "aws:SecureTransport": "false"
+        }
+      }
+    }
+  ]
+}`),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	runAdaptTest(t, filepath.Join("testdata", "s3_policy", "tfplan.json"), expected)
+}
+
+func TestAdaptS3Extensions(t *testing.T) {
+	planPath := filepath.Join("testdata", "s3_extensions", "tfplan.json")
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			S3: s3.S3{
+				Buckets: []s3.Bucket{
+					{
+						Name: types.String("replica", types.Metadata{}),
+						LifecycleConfiguration: []s3.Rules{
+							{
+								Status: types.String("Enabled", types.Metadata{}),
+							},
+							{
+								Status: types.String("Enabled", types.Metadata{}),
+							},
+						},
+					},
+					{
+						Name: types.String("source", types.Metadata{}),
+						LifecycleConfiguration: []s3.Rules{
+							{
+								Status: types.String("Enabled", types.Metadata{}),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	runAdaptTest(t, planPath, expected)
+
+	expectedExtensions := []BucketExtension{
+		{
+			Name: types.String("replica", types.Metadata{}),
+			LifecycleRules: []LifecycleRule{
+				{
+					ID:                                 types.String("expire", types.Metadata{}),
+					Status:                             types.String("Enabled", types.Metadata{}),
+					Prefix:                             types.String("tmp/", types.Metadata{}),
+					ExpirationDays:                     types.Int(1, types.Metadata{}),
+					NoncurrentVersionExpirationDays:    types.Int(1, types.Metadata{}),
+					AbortIncompleteMultipartUploadDays: types.Int(1, types.Metadata{}),
+				},
+				{
+					ID:                    types.String("archive", types.Metadata{}),
+					Status:                types.String("Enabled", types.Metadata{}),
+					Prefix:                types.String("archive/", types.Metadata{}),
+					Tags:                  types.Map(map[string]string{"class": "cold"}, types.Metadata{}),
+					ObjectSizeGreaterThan: types.Int(1024, types.Metadata{}),
+					ExpirationDays:        types.Int(365, types.Metadata{}),
+				},
+			},
+		},
+		{
+			Name: types.String("source", types.Metadata{}),
+			LifecycleRules: []LifecycleRule{
+				{
+					ID:                                 types.String("logs", types.Metadata{}),
+					Status:                             types.String("Enabled", types.Metadata{}),
+					Prefix:                             types.String("logs/", types.Metadata{}),
+					Tags:                               types.Map(map[string]string{"autoclean": "true"}, types.Metadata{}),
+					ExpirationDays:                     types.Int(90, types.Metadata{}),
+					NoncurrentVersionExpirationDays:    types.Int(30, types.Metadata{}),
+					AbortIncompleteMultipartUploadDays: types.Int(7, types.Metadata{}),
+				},
+			},
+			Replication: &Replication{
+				Role: types.String("arn:aws:iam::123456789012:role/replication", types.Metadata{}),
+				Rules: []ReplicationRule{
+					{
+						ID:                types.String("all", types.Metadata{}),
+						Status:            types.String("Enabled", types.Metadata{}),
+						Prefix:            types.String("data/", types.Metadata{}),
+						DestinationBucket: types.String("replica", types.Metadata{}),
+						StorageClass:      types.String("STANDARD", types.Metadata{}),
+					},
+					{
+						ID:                types.String("tagged", types.Metadata{}),
+						Status:            types.String("Enabled", types.Metadata{}),
+						Tags:              types.Map(map[string]string{"replicate": "true"}, types.Metadata{}),
+						DestinationBucket: types.String("replica", types.Metadata{}),
+						StorageClass:      types.String("GLACIER", types.Metadata{}),
+					},
+				},
+			},
+			ObjectLock: &ObjectLock{
+				Enabled:       types.Bool(true, types.Metadata{}),
+				Mode:          types.String("COMPLIANCE", types.Metadata{}),
+				RetentionDays: types.Int(5, types.Metadata{}),
+			},
+		},
+	}
+
+	got := AdaptS3Extensions(readPlanGraph(t, planPath))
+	assert.Empty(t, diff(expectedExtensions, got))
+}
+
+func TestAdaptS3ACL(t *testing.T) {
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			S3: s3.S3{
+				Buckets: []s3.Bucket{
+					{
+						Name: types.String("enforced", types.Metadata{}),
+						ACL:  types.String("private", types.Metadata{}),
+					},
+					{
+						Name: types.String("grants", types.Metadata{}),
+						ACL:  types.String("public-read", types.Metadata{}),
+					},
+					{
+						Name: types.String("legacy", types.Metadata{}),
+						ACL:  types.String("public-read-write", types.Metadata{}),
+					},
+				},
+			},
+		},
+	}
+
+	runAdaptTest(t, filepath.Join("testdata", "s3_acl", "tfplan.json"), expected)
+}
+
+func TestAdaptS3AccountPublicAccessBlock(t *testing.T) {
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			S3: s3.S3{
+				Buckets: []s3.Bucket{
+					{
+						Name: types.String("with-block", types.Metadata{}),
+						PublicAccessBlock: &s3.PublicAccessBlock{
+							BlockPublicACLs:       types.Bool(true, types.Metadata{}),
+							BlockPublicPolicy:     types.Bool(true, types.Metadata{}),
+							IgnorePublicACLs:      types.Bool(true, types.Metadata{}),
+							RestrictPublicBuckets: types.Bool(false, types.Metadata{}),
+						},
+						Objects: []s3.Contents{{}, {}},
+					},
+					{
+						Name: types.String("without-block", types.Metadata{}),
+						PublicAccessBlock: &s3.PublicAccessBlock{
+							BlockPublicACLs:       types.Bool(true, types.Metadata{}),
+							BlockPublicPolicy:     types.Bool(true, types.Metadata{}),
+							IgnorePublicACLs:      types.Bool(false, types.Metadata{}),
+							RestrictPublicBuckets: types.Bool(false, types.Metadata{}),
+						},
+					},
+				},
+			},
 		},
 	}
 
-	runAdaptTest(t, filepath.Join("testdata", "s3", "tfplan.json"), expected)
+	runAdaptTest(t, filepath.Join("testdata", "s3_account", "tfplan.json"), expected)
 }
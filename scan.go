@@ -0,0 +1,102 @@
+package tfplanadapt
+
+import (
+	"sort"
+
+	"github.com/aquasecurity/defsec/pkg/framework"
+	"github.com/aquasecurity/defsec/pkg/rules"
+	"github.com/aquasecurity/defsec/pkg/scan"
+	"github.com/aquasecurity/defsec/pkg/state"
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// FindingStatus describes how a finding is affected by the plan
+type FindingStatus string
+
+const (
+	// FindingNew is a finding introduced by the plan
+	FindingNew FindingStatus = "new"
+	// FindingFixed is a finding that is fixed by the plan
+	FindingFixed FindingStatus = "fixed"
+	// FindingPreExisting is a finding that exists before and after applying the plan
+	FindingPreExisting FindingStatus = "pre-existing"
+)
+
+// Finding represents a failed check result and its status relative to the plan
+type Finding struct {
+	Status FindingStatus
+	Result scan.Result
+}
+
+// Scan runs the registered checks against the state and returns the failed results.
+// Results of unmanaged blocks are skipped, as they do not belong to any resource of the plan
+func Scan(s *state.State) scan.Results {
+	var results scan.Results
+	for _, rule := range rules.GetRegistered(framework.Default) {
+		evaluated := rule.Evaluate(s)
+		for _, result := range evaluated.GetFailed() {
+			if result.Metadata().IsUnmanaged() {
+				continue
+			}
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// ScanChanges adapts the states before and after applying the plan, runs the checks on both
+// of them and reports the failed results as new, fixed or pre-existing
+func ScanChanges(plan *tfjson.Plan) ([]Finding, error) {
+	before, after, err := NewTerraformChangeGraphs(plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffResults(Scan(Adapt(before)), Scan(Adapt(after))), nil
+}
+
+// diffResults matches the results before and after applying the plan by their keys.
+// The results with the same key are matched one to one, so they are never collapsed
+func diffResults(before, after scan.Results) []Finding {
+	unmatched := make(map[string][]scan.Result, len(before))
+	for _, result := range before {
+		key := resultKey(result)
+		unmatched[key] = append(unmatched[key], result)
+	}
+
+	var findings []Finding
+	for _, result := range after {
+		key := resultKey(result)
+		status := FindingNew
+		if matched := unmatched[key]; len(matched) > 0 {
+			unmatched[key] = matched[1:]
+			status = FindingPreExisting
+		}
+		findings = append(findings, Finding{Status: status, Result: result})
+	}
+
+	for _, results := range unmatched {
+		for _, result := range results {
+			findings = append(findings, Finding{Status: FindingFixed, Result: result})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		keyI, keyJ := resultKey(findings[i].Result), resultKey(findings[j].Result)
+		if keyI != keyJ {
+			return keyI < keyJ
+		}
+		return findings[i].Status < findings[j].Status
+	})
+	return findings
+}
+
+// resultKey identifies the result by the check, the resource address and the description.
+// If the failed block has no reference, the address of the resource it belongs to is used
+func resultKey(result scan.Result) string {
+	reference := result.Metadata().Reference()
+	if reference == "" {
+		reference = result.Metadata().Root().Reference()
+	}
+	return result.Rule().AVDID + "|" + reference + "|" + result.Description()
+}
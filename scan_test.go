@@ -0,0 +1,72 @@
+package tfplanadapt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aquasecurity/defsec/pkg/scan"
+	"github.com/aquasecurity/defsec/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanChanges(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "changes", "tfplan.json"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	plan, err := ReadPlan(f)
+	require.NoError(t, err)
+
+	findings, err := ScanChanges(plan)
+	require.NoError(t, err)
+
+	statuses := func(avdID string) map[string][]FindingStatus {
+		got := make(map[string][]FindingStatus)
+		for _, finding := range findings {
+			if finding.Result.Rule().AVDID == avdID {
+				ref := finding.Result.Metadata().Reference()
+				got[ref] = append(got[ref], finding.Status)
+			}
+		}
+		return got
+	}
+
+	// AVD-AWS-0086: S3 Access block should block public ACL
+	assert.Equal(t, map[string][]FindingStatus{
+		"aws_s3_bucket.legacy": {FindingPreExisting},
+		"aws_s3_bucket.fixed":  {FindingFixed},
+		"aws_s3_bucket.new":    {FindingNew},
+	}, statuses("AVD-AWS-0086"))
+
+	// AVD-AWS-0088: S3 encryption should be enabled.
+	// The findings of all buckets have the same description, so they must not be collapsed
+	assert.Equal(t, map[string][]FindingStatus{
+		"aws_s3_bucket.legacy": {FindingPreExisting},
+		"aws_s3_bucket.fixed":  {FindingPreExisting},
+		"aws_s3_bucket.new":    {FindingNew},
+	}, statuses("AVD-AWS-0088"))
+}
+
+func TestDiffResults(t *testing.T) {
+	newResults := func(refs ...string) scan.Results {
+		var results scan.Results
+		for _, ref := range refs {
+			results.Add("Bucket does not have encryption enabled", types.NewMetadata(types.NewRange("", 0, 0, "", nil), ref))
+		}
+		results.SetRule(scan.Rule{AVDID: "AVD-AWS-0088"})
+		return results
+	}
+
+	findings := diffResults(
+		newResults("aws_s3_bucket.logs", "aws_s3_bucket.logs"),
+		newResults("aws_s3_bucket.logs", "aws_s3_bucket.logs", "aws_s3_bucket.logs"),
+	)
+
+	var statuses []FindingStatus
+	for _, finding := range findings {
+		statuses = append(statuses, finding.Status)
+	}
+	assert.Equal(t, []FindingStatus{FindingNew, FindingPreExisting, FindingPreExisting}, statuses)
+}
@@ -0,0 +1,23 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/sns"
+)
+
+func adaptSNS(g *Graph) sns.SNS {
+	var topics []sns.Topic
+	for _, res := range g.FindResourcesByType("aws_sns_topic") {
+		topics = append(topics, sns.Topic{
+			Metadata: res.Metadata(),
+			ARN:      res.GetStringAttr("arn"),
+			Encryption: sns.Encryption{
+				Metadata: res.Metadata(),
+				KMSKeyID: getKMSKeyID(g, res, "kms_master_key_id"),
+			},
+		})
+	}
+
+	return sns.SNS{
+		Topics: topics,
+	}
+}
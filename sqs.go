@@ -0,0 +1,57 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/iam"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/sqs"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptSQS(g *Graph) sqs.SQS {
+	var queues []sqs.Queue
+	for _, res := range g.FindResourcesByType("aws_sqs_queue") {
+		queue := sqs.Queue{
+			Metadata:   res.Metadata(),
+			QueueURL:   res.GetStringAttr("url"),
+			Encryption: adaptQueueEncryption(g, res),
+		}
+
+		// the policy attribute of the queue is ignored if the queue has separate policy resources
+		policyResources := res.FindAllBackRelatedOrMatching(g, "aws_sqs_queue_policy", "queue_url", "url", "id")
+		if len(policyResources) == 0 {
+			policyResources = []*Node{res}
+		}
+
+		for _, policyRes := range policyResources {
+			if document, ok := adaptPolicyDocument(policyRes, "policy"); ok {
+				queue.Policies = append(queue.Policies, iam.Policy{
+					Metadata: policyRes.Metadata(),
+					Name:     types.StringDefault("", policyRes.Metadata()),
+					Document: document,
+					Builtin:  types.BoolDefault(false, policyRes.Metadata()),
+				})
+			}
+		}
+
+		queues = append(queues, queue)
+	}
+
+	return sqs.SQS{
+		Queues: queues,
+	}
+}
+
+func adaptQueueEncryption(g *Graph, res *Node) sqs.Encryption {
+	encryption := sqs.Encryption{
+		Metadata:          res.Metadata(),
+		KMSKeyID:          getKMSKeyID(g, res, "kms_master_key_id"),
+		ManagedEncryption: res.GetBoolAttr("sqs_managed_sse_enabled"),
+	}
+
+	// SSE-SQS is enabled by default for new queues that are not encrypted with the KMS key
+	if res.GetAttr("sqs_managed_sse_enabled").IsNil() && encryption.KMSKeyID.IsEmpty() &&
+		!res.hasReference("kms_master_key_id") {
+		encryption.ManagedEncryption = types.BoolDefault(true, res.Metadata())
+	}
+
+	return encryption
+}
@@ -0,0 +1,31 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/ssm"
+)
+
+func adaptSSM(g *Graph) ssm.SSM {
+	var secrets []ssm.Secret
+	for _, res := range g.FindResourcesByType("aws_secretsmanager_secret") {
+		secrets = append(secrets, ssm.Secret{
+			Metadata: res.Metadata(),
+			KMSKeyID: getKMSKeyID(g, res, "kms_key_id"),
+		})
+	}
+
+	// only the secure string parameters are encrypted
+	for _, res := range g.FindResourcesByType("aws_ssm_parameter") {
+		if !res.GetStringAttr("type").EqualTo("SecureString") {
+			continue
+		}
+
+		secrets = append(secrets, ssm.Secret{
+			Metadata: res.Metadata(),
+			KMSKeyID: getKMSKeyID(g, res, "key_id"),
+		})
+	}
+
+	return ssm.SSM{
+		Secrets: secrets,
+	}
+}
@@ -0,0 +1,110 @@
+package tfplanadapt
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aquasecurity/defsec/pkg/providers/aws"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/cloudwatch"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/efs"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/kinesis"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/kms"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/mq"
+	"github.com/aquasecurity/defsec/pkg/providers/aws/msk"
+	"github.com/aquasecurity/defsec/pkg/state"
+	"github.com/aquasecurity/defsec/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptStreaming(t *testing.T) {
+
+	expected := &state.State{
+		AWS: aws.AWS{
+			Meta: defaultProviderMeta,
+			KMS: kms.KMS{
+				Keys: []kms.Key{
+					{
+						Usage:           types.String("ENCRYPT_DECRYPT", types.Metadata{}),
+						RotationEnabled: types.Bool(true, types.Metadata{}),
+					},
+				},
+			},
+			CloudWatch: cloudwatch.CloudWatch{
+				LogGroups: []cloudwatch.LogGroup{
+					{
+						Arn:  types.StringUnresolvable(types.Metadata{}),
+						Name: types.String("kafka", types.Metadata{}),
+					},
+				},
+			},
+			EFS: efs.EFS{
+				FileSystems: []efs.FileSystem{
+					{
+						Encrypted: types.Bool(true, types.Metadata{}),
+					},
+					{},
+				},
+			},
+			Kinesis: kinesis.Kinesis{
+				Streams: []kinesis.Stream{
+					{
+						Encryption: kinesis.Encryption{
+							Type: types.String("NONE", types.Metadata{}),
+						},
+					},
+					{
+						Encryption: kinesis.Encryption{
+							Type:     types.String(kinesis.EncryptionTypeKMS, types.Metadata{}),
+							KMSKeyID: types.StringUnresolvable(types.Metadata{}),
+						},
+					},
+				},
+			},
+			MSK: msk.MSK{
+				Clusters: []msk.Cluster{
+					{
+						EncryptionInTransit: msk.EncryptionInTransit{
+							ClientBroker: types.String(msk.ClientBrokerEncryptionTLS, types.Metadata{}),
+						},
+					},
+					{
+						EncryptionInTransit: msk.EncryptionInTransit{
+							ClientBroker: types.String(msk.ClientBrokerEncryptionTLSOrPlaintext, types.Metadata{}),
+						},
+						EncryptionAtRest: msk.EncryptionAtRest{
+							KMSKeyARN: types.StringUnresolvable(types.Metadata{}),
+							Enabled:   types.Bool(true, types.Metadata{}),
+						},
+						Logging: msk.Logging{
+							Broker: msk.BrokerLogging{
+								Cloudwatch: msk.CloudwatchLogging{
+									Enabled: types.Bool(true, types.Metadata{}),
+								},
+							},
+						},
+					},
+				},
+			},
+			MQ: mq.MQ{
+				Brokers: []mq.Broker{
+					{
+						Logging: mq.Logging{
+							General: types.Bool(true, types.Metadata{}),
+							Audit:   types.Bool(true, types.Metadata{}),
+						},
+					},
+					{
+						PublicAccess: types.Bool(true, types.Metadata{}),
+					},
+				},
+			},
+		},
+	}
+
+	got := runAdaptTest(t, filepath.Join("testdata", "streaming", "tfplan.json"), expected)
+
+	// the stream and the cluster are encrypted with the key created by the plan
+	assert.False(t, got.AWS.Kinesis.Streams[1].Encryption.KMSKeyID.IsEmpty())
+	assert.False(t, got.AWS.MSK.Clusters[1].EncryptionAtRest.KMSKeyARN.IsEmpty())
+	assert.True(t, got.AWS.MSK.Clusters[0].EncryptionAtRest.KMSKeyARN.IsEmpty())
+}
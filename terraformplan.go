@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 
 	tfjson "github.com/hashicorp/terraform-json"
@@ -22,9 +23,16 @@ func NewTerraformPlanGraph(plan *tfjson.Plan) (*Graph, error) {
 	graph := NewGraph()
 
 	fillNodes(graph, plan.PlannedValues.RootModule)
+	if plan.PriorState != nil && plan.PriorState.Values != nil {
+		fillDataNodes(graph, plan.PriorState.Values.RootModule)
+	}
 	fillEdges(graph, configModule{
 		ConfigModule: plan.Config.RootModule,
 	})
+	graph.providers = parseProviderConfigs(plan)
+	fillProviders(graph, configModule{
+		ConfigModule: plan.Config.RootModule,
+	}, graph.providers)
 
 	return graph, nil
 }
@@ -34,34 +42,117 @@ func fillNodes(g *Graph, module *tfjson.StateModule) {
 		return
 	}
 	for _, resource := range module.Resources {
-		moduleName := ""
-		if module.Address != "" {
-			parts := strings.Split(module.Address, ".")
-			moduleName = parts[len(parts)-1]
+		g.AddNode(newNode(
+			module.Address, resource.Address, resource.Type, resource.Name, resource.AttributeValues,
+		))
+	}
+
+	for _, module := range module.ChildModules {
+		fillNodes(g, module)
+	}
+}
+
+// fillDataNodes adds data sources that were read during planning.
+// Such data sources are only present in the prior state.
+func fillDataNodes(g *Graph, module *tfjson.StateModule) {
+	if module == nil {
+		return
+	}
+	for _, resource := range module.Resources {
+		if resource.Mode != tfjson.DataResourceMode || g.GetResource(resource.Address) != nil {
+			continue
 		}
-		node := Node{
-			resourceType: resource.Type,
-			resourceName: resource.Name,
-			moduleName:   moduleName,
-			Address:      resource.Address,
-			attributes:   make(map[string]*Attribute, len(resource.AttributeValues)),
+		g.AddNode(newNode(
+			module.Address, resource.Address, resource.Type, resource.Name, resource.AttributeValues,
+		))
+	}
+
+	for _, module := range module.ChildModules {
+		fillDataNodes(g, module)
+	}
+}
+
+// NewTerraformChangeGraphs builds graphs of the resources before and after applying the plan
+// based on the resource changes
+func NewTerraformChangeGraphs(plan *tfjson.Plan) (*Graph, *Graph, error) {
+	if plan == nil {
+		return nil, nil, errors.New("plan is nil")
+	}
+
+	before, after := NewGraph(), NewGraph()
+	providers := parseProviderConfigs(plan)
+
+	for _, change := range plan.ResourceChanges {
+		if change.Change == nil {
+			continue
 		}
 
-		for key, attr := range resource.AttributeValues {
-			node.attributes[key] = &Attribute{val: attr}
+		if values, ok := change.Change.Before.(map[string]any); ok {
+			before.AddNode(newNode(change.ModuleAddress, change.Address, change.Type, change.Name, values))
+		}
+
+		if values, ok := change.Change.After.(map[string]any); ok {
+			after.AddNode(newNode(change.ModuleAddress, change.Address, change.Type, change.Name, values))
 		}
-		g.AddNode(node)
 	}
 
-	for _, module := range module.ChildModules {
-		fillNodes(g, module)
+	for _, g := range []*Graph{before, after} {
+		if plan.PriorState != nil && plan.PriorState.Values != nil {
+			fillDataNodes(g, plan.PriorState.Values.RootModule)
+		}
+		if plan.Config != nil {
+			fillEdges(g, configModule{
+				ConfigModule: plan.Config.RootModule,
+			})
+			g.providers = providers
+			fillProviders(g, configModule{
+				ConfigModule: plan.Config.RootModule,
+			}, providers)
+		}
+	}
+
+	return before, after, nil
+}
+
+func newNode(moduleAddress, address, resourceType, resourceName string, values map[string]any) Node {
+	node := Node{
+		resourceType: resourceType,
+		resourceName: resourceName,
+		modulePath:   trimModuleKeys(moduleAddress),
+		Address:      address,
+		attributes:   make(map[string]*Attribute, len(values)),
 	}
+
+	for key, attr := range values {
+		node.attributes[key] = &Attribute{val: attr, metadata: node.Metadata()}
+	}
+	return node
 }
 
+// configModule is the module configuration with the context of its call
 type configModule struct {
 	*tfjson.ConfigModule
-	name  string
-	exprs expressions
+	// address is the address of the module, e.g. module.eks.module.kms
+	address string
+	// exprs are the arguments of the module call
+	exprs  expressions
+	parent *configModule
+}
+
+func (m configModule) child(callName string, moduleCall *tfjson.ModuleCall) configModule {
+	return configModule{
+		ConfigModule: moduleCall.Module,
+		address:      joinModuleAddress(m.address, callName),
+		exprs:        moduleCall.Expressions,
+		parent:       &m,
+	}
+}
+
+func joinModuleAddress(parent, callKey string) string {
+	if parent == "" {
+		return "module." + callKey
+	}
+	return parent + ".module." + callKey
 }
 
 func fillEdges(g *Graph, module configModule) {
@@ -69,86 +160,88 @@ func fillEdges(g *Graph, module configModule) {
 		return
 	}
 	for _, resource := range module.Resources {
+		// the resource may have several instances if it or one of its modules uses count or for_each
+		fromNodes := findConfigResourceNodes(g, module.address, resource)
+		if len(fromNodes) == 0 {
+			continue
+		}
+
 		for attrPath, refs := range findReferences(resource.Expressions, module) {
 			for _, ref := range refs {
-				// parts := strings.Split(ref, ".")
-				if ref.len() < 3 {
-					continue
-				}
-				fromAddress := resource.Address
-				if module.name != "" {
-					fromAddress = "module." + module.name + "." + fromAddress
-				}
-
-				toAddress := ref.address()
-				toAttr := ref.attribute()
-
 				linkAttrs := map[string]string{
-					attrPath: toAttr,
+					attrPath: ref.attribute(),
 				}
 
-				// If the resource has an index, we don't know the exact address of the resource
-				// TODO support for-each
-				if resource.CountExpression != nil {
-					g.AddEdgeFromResources(module.name, resource.Type, resource.Name, toAddress, linkAttrs)
-				} else {
-					g.AddEdge(fromAddress, toAddress, linkAttrs)
+				for _, fromNode := range fromNodes {
+					for _, toNode := range g.findResourceInstances(ref.address(), fromNode) {
+						g.AddEdge(fromNode.ID(), toNode.ID(), linkAttrs)
+					}
 				}
 			}
 		}
 	}
 
-	for moduleName, moduleCall := range module.ModuleCalls {
-		fillEdges(g, configModule{
-			name:         moduleName,
-			exprs:        moduleCall.Expressions,
-			ConfigModule: moduleCall.Module,
-		})
+	for callName, moduleCall := range module.ModuleCalls {
+		fillEdges(g, module.child(callName, moduleCall))
 	}
 }
 
-type expressions map[string]*tfjson.Expression
+// findConfigResourceNodes returns the instances of the resource declared in the module configuration
+func findConfigResourceNodes(g *Graph, modulePath string, resource *tfjson.ConfigResource) []*Node {
+	address := resource.Address
+	if modulePath != "" {
+		address = modulePath + "." + address
+	}
+
+	if node := g.GetResource(address); node != nil {
+		return []*Node{node}
+	}
 
-type referenceType int
+	var nodes []*Node
+	for _, node := range g.FindResources(modulePath, resource.Type, resource.Name) {
+		if node.isDataSource() == (resource.Mode == tfjson.DataResourceMode) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
 
-const (
-	moduleReference referenceType = iota
-	varReference
-	localReference
-)
+type expressions map[string]*tfjson.Expression
 
+// reference is a reference to the resource attribute resolved to the module
+// that declares the resource
 type reference struct {
-	typ        referenceType
-	moduleName string
-	val        string
+	// module is the address of the module, e.g. module.eks.module.kms
+	module string
+	// val is the reference relative to the module, e.g. aws_kms_key.this[0].arn
+	val string
 }
 
 func (r reference) address() string {
 	parts := r.split()
-	switch r.typ {
-	case localReference, varReference:
-		if parts[0] == "module" {
-			return strings.Join(parts[:4], ".")
-		}
-		return strings.Join(parts[:2], ".")
-	case moduleReference:
-		return "module." + r.moduleName + "." + strings.Join(parts[:2], ".")
-	default:
-		panic("unsupported ref type")
+	address := strings.Join(parts[:addressLen(parts)], ".")
+	if r.module != "" {
+		address = r.module + "." + address
 	}
+	return address
 }
 
 func (r reference) attribute() string {
 	parts := r.split()
-	return parts[2]
+	return parts[addressLen(parts)]
 }
 
-func (r reference) split() []string {
-	return strings.Split(r.val, ".")
+// addressLen returns the number of parts of the resource address in the reference,
+// data source addresses have the "data" prefix
+func addressLen(parts []string) int {
+	if parts[0] == "data" {
+		return 3
+	}
+	return 2
 }
 
-func (r reference) len() int {
-	return len(r.split())
+func (r reference) split() []string {
+	return splitAddress(r.val)
 }
 
 func findReferences(exprs expressions, module configModule) map[string][]reference {
@@ -166,57 +259,21 @@ func findReferences(exprs expressions, module configModule) map[string][]referen
 			}
 
 			if len(expr.References) > 0 {
-				refs := make([]reference, 0, len(expr.References))
+				var refs []reference
 				for _, ref := range expr.References {
-					parts := strings.Split(ref, ".")
-					// if the reference points to the module output, it has the following format:
-					// "module.module_name.output_name" otherwise "resource_type.resource_name.attribute_path"
-
-					// if the attribute refers to the module output, we must find the source reference
-					if parts[0] == "module" && len(parts) == 3 {
-						moduleCall, exists := module.ModuleCalls[parts[1]]
-						if !exists {
-							continue
-						}
-						childModule := moduleCall.Module
-						output, exists := childModule.Outputs[parts[2]]
-						if !exists {
-							continue
-						}
-						outputExprs := expressions{parts[2]: output.Expression}
-						childRefs := findReferences(outputExprs, configModule{
-							name:         parts[1],
-							exprs:        moduleCall.Expressions,
-							ConfigModule: childModule,
-						})
-						for _, resolvedRefs := range childRefs {
-							for _, resolvedRef := range resolvedRefs {
-								refs = append(refs, reference{
-									typ:        moduleReference,
-									moduleName: parts[1],
-									val:        resolvedRef.val,
-								})
-							}
-						}
-					} else if parts[0] == "var" && module.exprs != nil {
-						moduleRefs := findReferences(module.exprs, module)
-						for _, resolvedRefs := range moduleRefs {
-							for _, reresolvedRef := range resolvedRefs {
-								refs = append(refs, reference{
-									typ: varReference,
-									val: reresolvedRef.val,
-								})
-							}
-						}
-					} else if len(parts) == 3 {
-						typ := localReference
-						if module.name != "" {
-							typ = moduleReference
-						}
-						refs = append(refs, reference{
-							moduleName: module.name,
-							typ:        typ,
-							val:        ref,
+					parts := splitAddress(ref)
+					switch {
+					// the reference to the module output has the format "module.module_name.output_name",
+					// the output is resolved to the references of its expression
+					case parts[0] == "module" && len(parts) == 3:
+						refs = appendReferences(refs, findOutputReferences(module, parts[1], parts[2])...)
+					// the variable is resolved to the references of the module call argument
+					case parts[0] == "var":
+						refs = appendReferences(refs, findVariableReferences(module, parts[1])...)
+					case len(parts) == addressLen(parts)+1:
+						refs = appendReferences(refs, reference{
+							module: module.address,
+							val:    ref,
 						})
 					}
 				}
@@ -233,6 +290,58 @@ func findReferences(exprs expressions, module configModule) map[string][]referen
 	return refsMap
 }
 
+// findOutputReferences returns the references of the output of the module called by the given module.
+// The call key may contain the instance key, e.g. kms[0]
+func findOutputReferences(module configModule, callKey, outputName string) []reference {
+	moduleCall, exists := module.ModuleCalls[trimInstanceKey(callKey)]
+	if !exists || moduleCall.Module == nil {
+		return nil
+	}
+
+	output, exists := moduleCall.Module.Outputs[outputName]
+	if !exists {
+		return nil
+	}
+
+	child := module.child(trimInstanceKey(callKey), moduleCall)
+	// the instance of the module is known from the reference
+	child.address = joinModuleAddress(module.address, callKey)
+	return flattenReferences(findReferences(expressions{outputName: output.Expression}, child))
+}
+
+// findVariableReferences returns the references of the argument that the parent module passes to the variable
+func findVariableReferences(module configModule, name string) []reference {
+	if module.parent == nil {
+		return nil
+	}
+
+	expr, exists := module.exprs[name]
+	if !exists {
+		return nil
+	}
+
+	return flattenReferences(findReferences(expressions{name: expr}, *module.parent))
+}
+
+func flattenReferences(refsMap map[string][]reference) []reference {
+	var refs []reference
+	for _, r := range refsMap {
+		refs = appendReferences(refs, r...)
+	}
+	return refs
+}
+
+// appendReferences appends the references that are not in the list yet,
+// e.g. both "var.config" and "var.config.name" are resolved to the same references
+func appendReferences(refs []reference, newRefs ...reference) []reference {
+	for _, newRef := range newRefs {
+		if !slices.Contains(refs, newRef) {
+			refs = append(refs, newRef)
+		}
+	}
+	return refs
+}
+
 func ReadPlan(r io.Reader) (*tfjson.Plan, error) {
 	var plan tfjson.Plan
 	decoder := json.NewDecoder(r)
@@ -4,15 +4,40 @@ import (
 	"os"
 	"testing"
 
+	"github.com/aquasecurity/defsec/pkg/providers/aws"
 	"github.com/aquasecurity/defsec/pkg/state"
 	"github.com/aquasecurity/defsec/pkg/types"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/liamg/iamgo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func runAdaptTest(t *testing.T, planPath string, expected *state.State) {
+// defaultProviderMeta is the provider configuration of the plans that configure the AWS provider
+// from the environment only
+var defaultProviderMeta = aws.Meta{
+	TFProviders: []aws.TerraformProvider{
+		{
+			Alias:  types.String("", types.Metadata{}),
+			Region: types.String("", types.Metadata{}),
+			DefaultTags: aws.DefaultTags{
+				Tags: types.Map(nil, types.Metadata{}),
+			},
+		},
+	},
+}
+
+// ignoreProviders excludes the provider configurations from the comparison
+var ignoreProviders = cmpopts.IgnoreFields(aws.AWS{}, "Meta")
+
+func runAdaptTest(t *testing.T, planPath string, expected *state.State, opts ...cmp.Option) *state.State {
+	got := Adapt(readPlanGraph(t, planPath))
+	assert.Empty(t, diffState(expected, got, opts...))
+	return got
+}
+
+func readPlanGraph(t *testing.T, planPath string) *Graph {
 	f, err := os.Open(planPath)
 	require.NoError(t, err)
 	defer f.Close()
@@ -22,17 +47,30 @@ func runAdaptTest(t *testing.T, planPath string, expected *state.State) {
 
 	graph, err := NewTerraformPlanGraph(plan)
 	require.NoError(t, err)
-
-	got := Adapt(graph)
-	assert.Empty(t, diffState(expected, got))
+	return graph
 }
 
 func diffState(expected *state.State, actual *state.State, opts ...cmp.Option) string {
+	return diff(expected, actual, opts...)
+}
+
+func diff(expected any, actual any, opts ...cmp.Option) string {
 	opts = append(
 		opts,
 		cmpopts.IgnoreUnexported(state.State{}, types.Metadata{}, types.BaseAttribute{}),
-		cmp.AllowUnexported(types.BoolValue{}, types.IntValue{}, types.StringValue{}),
+		cmp.AllowUnexported(types.BoolValue{}, types.IntValue{}, types.StringValue{}, types.MapValue{}, types.TimeValue{}),
+		cmp.Comparer(func(a, b iamgo.Document) bool {
+			aJSON, _ := a.MarshalJSON()
+			bJSON, _ := b.MarshalJSON()
+			return string(aJSON) == string(bJSON)
+		}),
 	)
 
 	return cmp.Diff(expected, actual, opts...)
 }
+
+func mustParsePolicy(t *testing.T, policy string) iamgo.Document {
+	parsed, err := iamgo.ParseString(policy)
+	require.NoError(t, err)
+	return *parsed
+}
@@ -0,0 +1,87 @@
+// tfplan.json is written by hand for this config, it is not generated by Terraform
+
+terraform {
+  required_version = ">= 1.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "= 5.34.0"
+    }
+  }
+}
+
+resource "aws_kms_key" "analytics" {
+  description         = "analytics"
+  enable_key_rotation = true
+}
+
+resource "aws_neptune_cluster" "graph" {
+  cluster_identifier             = "graph"
+  engine                         = "neptune"
+  storage_encrypted              = true
+  kms_key_arn                    = aws_kms_key.analytics.arn
+  enable_cloudwatch_logs_exports = ["audit"]
+}
+
+resource "aws_neptune_cluster" "legacy" {
+  cluster_identifier = "legacy"
+  engine             = "neptune"
+}
+
+resource "aws_docdb_cluster" "docs" {
+  cluster_identifier              = "docs"
+  master_username                 = "admin"
+  master_password                 = "password123456"
+  storage_encrypted               = true
+  kms_key_id                      = "arn:aws:kms:us-east-1:111111111111:key/docs"
+  backup_retention_period         = 7
+  enabled_cloudwatch_logs_exports = ["audit", "profiler"]
+}
+
+resource "aws_docdb_cluster_instance" "docs" {
+  identifier         = "docs-1"
+  cluster_identifier = aws_docdb_cluster.docs.id
+  instance_class     = "db.r5.large"
+}
+
+resource "aws_docdb_cluster_instance" "external" {
+  identifier         = "external-1"
+  cluster_identifier = "external"
+  instance_class     = "db.r5.large"
+}
+
+resource "aws_athena_database" "reports" {
+  name   = "reports"
+  bucket = "reports-bucket"
+
+  encryption_configuration {
+    encryption_option = "SSE_KMS"
+    kms_key           = aws_kms_key.analytics.arn
+  }
+}
+
+resource "aws_athena_database" "scratch" {
+  name   = "scratch"
+  bucket = "scratch-bucket"
+}
+
+resource "aws_athena_workgroup" "primary" {
+  name = "primary"
+
+  configuration {
+    enforce_workgroup_configuration = false
+
+    result_configuration {
+      output_location = "s3://reports-bucket/output/"
+
+      encryption_configuration {
+        encryption_option = "SSE_S3"
+      }
+    }
+  }
+}
+
+resource "aws_athena_workgroup" "adhoc" {
+  name = "adhoc"
+}
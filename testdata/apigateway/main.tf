@@ -0,0 +1,112 @@
+// tfplan.json is written by hand for this config, it is not generated by Terraform
+
+terraform {
+  required_version = ">= 1.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "= 5.34.0"
+    }
+  }
+}
+
+resource "aws_cloudwatch_log_group" "access" {
+  name = "api-access"
+}
+
+resource "aws_api_gateway_rest_api" "main" {
+  name = "main"
+}
+
+resource "aws_api_gateway_resource" "users" {
+  rest_api_id = aws_api_gateway_rest_api.main.id
+  parent_id   = aws_api_gateway_rest_api.main.root_resource_id
+  path_part   = "users"
+}
+
+resource "aws_api_gateway_method" "get_users" {
+  rest_api_id   = aws_api_gateway_rest_api.main.id
+  resource_id   = aws_api_gateway_resource.users.id
+  http_method   = "GET"
+  authorization = "NONE"
+}
+
+resource "aws_api_gateway_method" "root" {
+  rest_api_id      = aws_api_gateway_rest_api.main.id
+  resource_id      = aws_api_gateway_rest_api.main.root_resource_id
+  http_method      = "ANY"
+  authorization    = "AWS_IAM"
+  api_key_required = true
+}
+
+resource "aws_api_gateway_stage" "prod" {
+  rest_api_id          = aws_api_gateway_rest_api.main.id
+  deployment_id        = "abc123"
+  stage_name           = "prod"
+  xray_tracing_enabled = true
+
+  access_log_settings {
+    destination_arn = aws_cloudwatch_log_group.access.arn
+    format          = "$context.requestId"
+  }
+}
+
+resource "aws_api_gateway_method_settings" "all" {
+  rest_api_id = aws_api_gateway_rest_api.main.id
+  stage_name  = aws_api_gateway_stage.prod.stage_name
+  method_path = "*/*"
+
+  settings {
+    caching_enabled      = true
+    cache_data_encrypted = true
+  }
+}
+
+resource "aws_api_gateway_stage" "external" {
+  rest_api_id   = "a1b2c3d4e5"
+  deployment_id = "abc123"
+  stage_name    = "dev"
+}
+
+resource "aws_api_gateway_method" "external" {
+  rest_api_id   = "a1b2c3d4e5"
+  resource_id   = "f6g7h8"
+  http_method   = "POST"
+  authorization = "NONE"
+}
+
+resource "aws_api_gateway_domain_name" "api" {
+  domain_name              = "api.example.com"
+  regional_certificate_arn = "arn:aws:acm:us-east-1:111111111111:certificate/api"
+  security_policy          = "TLS_1_2"
+
+  endpoint_configuration {
+    types = ["REGIONAL"]
+  }
+}
+
+resource "aws_apigatewayv2_api" "http" {
+  name          = "http"
+  protocol_type = "HTTP"
+}
+
+resource "aws_apigatewayv2_stage" "default" {
+  api_id = aws_apigatewayv2_api.http.id
+  name   = "$default"
+}
+
+resource "aws_apigatewayv2_stage" "external" {
+  api_id = "x9y8z7"
+  name   = "dev"
+}
+
+resource "aws_apigatewayv2_domain_name" "http" {
+  domain_name = "http.example.com"
+
+  domain_name_configuration {
+    certificate_arn = "arn:aws:acm:us-east-1:111111111111:certificate/http"
+    endpoint_type   = "REGIONAL"
+    security_policy = "TLS_1_2"
+  }
+}
@@ -0,0 +1,34 @@
+// tfplan.json is written by hand for this config, it is not generated by Terraform
+// aws_s3_bucket.legacy and aws_s3_bucket.fixed already exist in the state
+
+terraform {
+  required_version = ">= 1.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "= 5.34.0"
+    }
+  }
+}
+
+resource "aws_s3_bucket" "legacy" {
+  bucket = "legacy"
+}
+
+resource "aws_s3_bucket" "fixed" {
+  bucket = "fixed"
+}
+
+resource "aws_s3_bucket_public_access_block" "fixed" {
+  bucket = aws_s3_bucket.fixed.id
+
+  block_public_acls       = true
+  block_public_policy     = true
+  ignore_public_acls      = true
+  restrict_public_buckets = true
+}
+
+resource "aws_s3_bucket" "new" {
+  bucket = "new"
+}
@@ -0,0 +1,71 @@
+// tfplan.json is written by hand for this config, it is not generated by Terraform
+
+terraform {
+  required_version = ">= 1.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "= 5.34.0"
+    }
+  }
+}
+
+resource "aws_s3_bucket" "trail" {
+  bucket = "trail-logs"
+}
+
+resource "aws_cloudwatch_log_group" "trail" {
+  name              = "trail"
+  retention_in_days = 365
+}
+
+resource "aws_cloudwatch_log_metric_filter" "trail_changes" {
+  name           = "CloudTrailChanges"
+  log_group_name = aws_cloudwatch_log_group.trail.name
+  pattern        = "{($.eventName=CreateTrail) || ($.eventName=UpdateTrail) || ($.eventName=DeleteTrail) || ($.eventName=StartLogging) || ($.eventName=StopLogging)}"
+
+  metric_transformation {
+    name      = "CloudTrailChanges"
+    namespace = "CIS"
+    value     = "1"
+  }
+}
+
+resource "aws_cloudwatch_metric_alarm" "trail_changes" {
+  alarm_name          = "trail-changes"
+  metric_name         = "CloudTrailChanges"
+  namespace           = "CIS"
+  comparison_operator = "GreaterThanOrEqualToThreshold"
+  evaluation_periods  = 1
+  period              = 300
+  statistic           = "Sum"
+  threshold           = 1
+
+  dimensions = {
+    Trail = "main"
+  }
+}
+
+resource "aws_cloudtrail" "main" {
+  name                       = "main"
+  s3_bucket_name             = aws_s3_bucket.trail.id
+  is_multi_region_trail      = true
+  enable_log_file_validation = true
+  cloud_watch_logs_group_arn = "${aws_cloudwatch_log_group.trail.arn}:*"
+
+  event_selector {
+    read_write_type = "WriteOnly"
+
+    data_resource {
+      type   = "AWS::S3::Object"
+      values = ["arn:aws:s3"]
+    }
+  }
+}
+
+resource "aws_cloudtrail" "regional" {
+  name           = "regional"
+  s3_bucket_name = "external-bucket"
+  enable_logging = false
+}
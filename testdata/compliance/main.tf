@@ -0,0 +1,85 @@
+// tfplan.json is written by hand for this config, it is not generated by Terraform
+
+terraform {
+  required_version = ">= 1.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "= 5.34.0"
+    }
+  }
+}
+
+resource "aws_kms_key" "secrets" {
+  description         = "secrets"
+  enable_key_rotation = true
+}
+
+resource "aws_codebuild_project" "build" {
+  name         = "build"
+  service_role = "arn:aws:iam::111111111111:role/codebuild"
+
+  artifacts {
+    type = "NO_ARTIFACTS"
+  }
+
+  secondary_artifacts {
+    type                = "S3"
+    artifact_identifier = "reports"
+    location            = "reports-bucket"
+    encryption_disabled = true
+  }
+
+  environment {
+    compute_type = "BUILD_GENERAL1_SMALL"
+    image        = "aws/codebuild/standard:7.0"
+    type         = "LINUX_CONTAINER"
+  }
+
+  source {
+    type     = "GITHUB"
+    location = "https://github.com/example/build.git"
+  }
+}
+
+resource "aws_config_configuration_aggregator" "account" {
+  name = "account"
+
+  account_aggregation_source {
+    account_ids = ["111111111111"]
+    regions     = ["us-east-1"]
+  }
+}
+
+resource "aws_config_configuration_aggregator" "organization" {
+  name = "organization"
+
+  organization_aggregation_source {
+    all_regions = true
+    role_arn    = "arn:aws:iam::111111111111:role/config"
+  }
+}
+
+resource "aws_secretsmanager_secret" "database" {
+  name       = "database"
+  kms_key_id = aws_kms_key.secrets.arn
+}
+
+resource "aws_secretsmanager_secret" "default" {
+  name       = "default"
+  kms_key_id = "alias/aws/secretsmanager"
+}
+
+resource "aws_ssm_parameter" "token" {
+  name   = "token"
+  type   = "SecureString"
+  value  = "secret"
+  key_id = aws_kms_key.secrets.key_id
+}
+
+resource "aws_ssm_parameter" "region" {
+  name  = "region"
+  type  = "String"
+  value = "us-east-1"
+}
@@ -0,0 +1,39 @@
+// tfplan.json is written by hand for this config, it is not generated by Terraform
+
+terraform {
+  required_version = ">= 1.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "= 5.34.0"
+    }
+  }
+}
+
+data "aws_s3_bucket" "shared" {
+  bucket = "shared"
+}
+
+data "aws_vpc" "default" {
+  default = true
+}
+
+resource "aws_subnet" "app" {
+  vpc_id     = data.aws_vpc.default.id
+  cidr_block = "172.31.96.0/20"
+}
+
+resource "aws_s3_bucket_policy" "shared" {
+  bucket = data.aws_s3_bucket.shared.id
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [{
+      Effect    = "Deny"
+      Principal = "*"
+      Action    = "s3:*"
+      Resource  = "${data.aws_s3_bucket.shared.arn}/*"
+      Condition = { Bool = { "aws:SecureTransport" = "false" } }
+    }]
+  })
+}
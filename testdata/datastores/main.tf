@@ -0,0 +1,135 @@
+// tfplan.json is written by hand for this config, it is not generated by Terraform
+
+terraform {
+  required_version = ">= 1.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "= 5.34.0"
+    }
+  }
+}
+
+resource "aws_vpc" "main" {
+  cidr_block = "10.0.0.0/16"
+}
+
+resource "aws_subnet" "a" {
+  vpc_id     = aws_vpc.main.id
+  cidr_block = "10.0.1.0/24"
+}
+
+resource "aws_kms_key" "data" {
+  description         = "data"
+  enable_key_rotation = true
+}
+
+resource "aws_elasticache_replication_group" "redis" {
+  replication_group_id       = "redis"
+  description                = "redis"
+  engine                     = "redis"
+  node_type                  = "cache.m5.large"
+  num_cache_clusters         = 1
+  snapshot_retention_limit   = 5
+  transit_encryption_enabled = true
+  at_rest_encryption_enabled = true
+}
+
+resource "aws_elasticache_cluster" "replica" {
+  cluster_id           = "replica"
+  replication_group_id = aws_elasticache_replication_group.redis.id
+}
+
+resource "aws_elasticache_cluster" "memcached" {
+  cluster_id      = "memcached"
+  engine          = "memcached"
+  node_type       = "cache.t3.micro"
+  num_cache_nodes = 1
+}
+
+resource "aws_redshift_subnet_group" "main" {
+  name       = "main"
+  subnet_ids = [aws_subnet.a.id]
+}
+
+resource "aws_redshift_cluster" "warehouse" {
+  cluster_identifier                  = "warehouse"
+  node_type                           = "ra3.xlplus"
+  number_of_nodes                     = 2
+  master_username                     = "admin"
+  manage_master_password              = true
+  encrypted                           = true
+  kms_key_id                          = aws_kms_key.data.arn
+  cluster_subnet_group_name           = aws_redshift_subnet_group.main.name
+  automated_snapshot_retention_period = 7
+}
+
+resource "aws_redshift_logging" "warehouse" {
+  cluster_identifier   = aws_redshift_cluster.warehouse.id
+  log_destination_type = "cloudwatch"
+  log_exports          = ["connectionlog", "userlog"]
+}
+
+resource "aws_redshift_parameter_group" "main" {
+  name   = "main"
+  family = "redshift-1.0"
+
+  parameter {
+    name  = "require_ssl"
+    value = "true"
+  }
+}
+
+resource "aws_cloudwatch_log_group" "audit" {
+  name = "opensearch-audit"
+}
+
+resource "aws_opensearch_domain" "logs" {
+  domain_name    = "logs"
+  engine_version = "OpenSearch_2.11"
+
+  cluster_config {
+    instance_type            = "r6g.large.search"
+    dedicated_master_enabled = true
+  }
+
+  vpc_options {
+    subnet_ids = [aws_subnet.a.id]
+  }
+
+  encrypt_at_rest {
+    enabled    = true
+    kms_key_id = aws_kms_key.data.arn
+  }
+
+  node_to_node_encryption {
+    enabled = true
+  }
+
+  domain_endpoint_options {
+    enforce_https       = true
+    tls_security_policy = "Policy-Min-TLS-1-2-2019-07"
+  }
+
+  log_publishing_options {
+    log_type                 = "AUDIT_LOGS"
+    cloudwatch_log_group_arn = aws_cloudwatch_log_group.audit.arn
+  }
+}
+
+resource "aws_opensearch_domain_policy" "logs" {
+  domain_name     = aws_opensearch_domain.logs.domain_name
+  access_policies = "{\"Version\":\"2012-10-17\",\"Statement\":[]}"
+}
+
+resource "aws_elasticsearch_domain" "legacy" {
+  domain_name           = "legacy"
+  elasticsearch_version = "6.8"
+  access_policies       = "{\"Version\":\"2012-10-17\",\"Statement\":[{\"Effect\":\"Allow\",\"Principal\":\"*\",\"Action\":\"es:*\"}]}"
+
+  domain_endpoint_options {
+    enforce_https       = false
+    tls_security_policy = "Policy-Min-TLS-1-0-2019-07"
+  }
+}
@@ -0,0 +1,104 @@
+// tfplan.json is written by hand for this config, it is not generated by Terraform
+
+terraform {
+  required_version = ">= 1.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "= 5.34.0"
+    }
+  }
+}
+
+resource "aws_launch_template" "web" {
+  name      = "web"
+  image_id  = "ami-12345678"
+  user_data = base64encode("#!/bin/bash\necho hello")
+
+  metadata_options {
+    http_endpoint = "enabled"
+    http_tokens   = "optional"
+  }
+
+  block_device_mappings {
+    device_name = "/dev/sdf"
+
+    ebs {
+      volume_size = 20
+      encrypted   = false
+    }
+  }
+}
+
+resource "aws_autoscaling_group" "web" {
+  max_size           = 2
+  min_size           = 1
+  availability_zones = ["us-east-1a"]
+
+  launch_template {
+    id      = aws_launch_template.web.id
+    version = "$Latest"
+  }
+}
+
+resource "aws_launch_template" "batch" {
+  name     = "batch"
+  image_id = "ami-12345678"
+
+  metadata_options {
+    http_endpoint = "enabled"
+    http_tokens   = "required"
+  }
+}
+
+resource "aws_autoscaling_group" "batch" {
+  max_size           = 4
+  min_size           = 0
+  availability_zones = ["us-east-1a"]
+
+  mixed_instances_policy {
+    launch_template {
+      launch_template_specification {
+        launch_template_id = aws_launch_template.batch.id
+        version            = "$Latest"
+      }
+
+      override {
+        instance_type = "c5.large"
+      }
+    }
+  }
+}
+
+resource "aws_launch_configuration" "legacy" {
+  name                        = "legacy"
+  image_id                    = "ami-12345678"
+  instance_type               = "t3.micro"
+  associate_public_ip_address = true
+
+  root_block_device {
+    encrypted = true
+  }
+
+  ebs_block_device {
+    device_name = "/dev/sdg"
+    encrypted   = false
+  }
+
+  metadata_options {
+    http_endpoint = "enabled"
+    http_tokens   = "required"
+  }
+}
+
+resource "aws_kms_key" "ebs" {
+  enable_key_rotation = true
+}
+
+resource "aws_ebs_volume" "data" {
+  availability_zone = "us-east-1a"
+  size              = 40
+  encrypted         = true
+  kms_key_id        = aws_kms_key.ebs.arn
+}
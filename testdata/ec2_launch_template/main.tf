@@ -0,0 +1,78 @@
+// tfplan.json is written by hand for this config, it is not generated by Terraform
+
+terraform {
+  required_version = ">= 1.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "= 5.34.0"
+    }
+  }
+}
+
+resource "aws_launch_template" "this" {
+  name      = "base"
+  image_id  = "ami-12345678"
+  user_data = base64encode("template data")
+
+  metadata_options {
+    http_endpoint = "enabled"
+    http_tokens   = "required"
+  }
+
+  block_device_mappings {
+    device_name = "/dev/xvda"
+
+    ebs {
+      encrypted = true
+    }
+  }
+
+  block_device_mappings {
+    device_name = "/dev/sdf"
+
+    ebs {
+      encrypted = false
+    }
+  }
+
+  block_device_mappings {
+    device_name = "/dev/sdg"
+
+    ebs {
+      encrypted = true
+    }
+  }
+}
+
+resource "aws_instance" "overrides" {
+  launch_template {
+    id = aws_launch_template.this.id
+  }
+
+  metadata_options {
+    http_tokens = "optional"
+  }
+
+  root_block_device {
+    volume_size = 30
+  }
+
+  ebs_block_device {
+    device_name = "/dev/sdf"
+    encrypted   = true
+  }
+
+  ebs_block_device {
+    device_name = "/dev/sdh"
+  }
+}
+
+resource "aws_instance" "user_data" {
+  user_data = "instance data"
+
+  launch_template {
+    id = aws_launch_template.this.id
+  }
+}
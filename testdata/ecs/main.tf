@@ -0,0 +1,118 @@
+// tfplan.json is written by hand for this config, it is not generated by Terraform
+
+terraform {
+  required_version = ">= 1.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "= 5.34.0"
+    }
+  }
+}
+
+resource "aws_ecs_cluster" "main" {
+  name = "main"
+
+  setting {
+    name  = "containerInsights"
+    value = "enabled"
+  }
+}
+
+resource "aws_ecs_cluster" "legacy" {
+  name = "legacy"
+}
+
+resource "aws_ecs_task_definition" "app" {
+  family = "app"
+  container_definitions = jsonencode([
+    {
+      name       = "app"
+      image      = "app:latest"
+      cpu        = 256
+      memory     = 512
+      essential  = true
+      privileged = true
+      portMappings = [
+        {
+          containerPort = 80
+          hostPort      = 80
+        }
+      ]
+      environment = [
+        {
+          name  = "DB_PASSWORD"
+          value = "secret"
+        }
+      ]
+    }
+  ])
+
+  volume {
+    name = "data"
+
+    efs_volume_configuration {
+      file_system_id     = "fs-12345678"
+      transit_encryption = "ENABLED"
+    }
+  }
+
+  volume {
+    name = "cache"
+
+    efs_volume_configuration {
+      file_system_id = "fs-12345678"
+    }
+  }
+}
+
+resource "aws_kms_key" "ecr" {
+  description = "ecr"
+}
+
+resource "aws_ecr_repository" "app" {
+  name                 = "app"
+  image_tag_mutability = "IMMUTABLE"
+
+  image_scanning_configuration {
+    scan_on_push = true
+  }
+
+  encryption_configuration {
+    encryption_type = "KMS"
+    kms_key         = aws_kms_key.ecr.arn
+  }
+}
+
+resource "aws_ecr_repository" "legacy" {
+  name = "legacy"
+}
+
+resource "aws_ecr_repository_policy" "app" {
+  repository = aws_ecr_repository.app.name
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect    = "Allow"
+        Principal = "*"
+        Action    = "ecr:GetDownloadUrlForLayer"
+      }
+    ]
+  })
+}
+
+resource "aws_ecr_repository_policy" "legacy" {
+  repository = "legacy"
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect    = "Allow"
+        Principal = { AWS = "arn:aws:iam::111111111111:root" }
+        Action    = "ecr:BatchGetImage"
+      }
+    ]
+  })
+}
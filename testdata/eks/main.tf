@@ -0,0 +1,39 @@
+// tfplan.json is written by hand for this config, it is not generated by Terraform
+
+terraform {
+  required_version = ">= 1.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "= 5.34.0"
+    }
+  }
+}
+
+module "eks" {
+  source  = "terraform-aws-modules/eks/aws"
+  version = "19.21.0"
+
+  cluster_name                         = "main"
+  cluster_enabled_log_types            = ["audit", "api", "authenticator"]
+  cluster_endpoint_public_access       = true
+  cluster_endpoint_public_access_cidrs = ["10.0.0.0/8"]
+  subnet_ids                           = ["subnet-12345678"]
+  vpc_id                               = "vpc-12345678"
+}
+
+module "kms" {
+  source  = "terraform-aws-modules/kms/aws"
+  version = "2.1.0"
+
+  description = "legacy clusters"
+}
+
+module "legacy" {
+  source   = "./modules/cluster"
+  for_each = toset(["dev"])
+
+  name        = each.key
+  kms_key_arn = module.kms.key_arn
+}
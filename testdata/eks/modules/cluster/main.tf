@@ -0,0 +1,25 @@
+variable "name" {
+  type = string
+}
+
+variable "kms_key_arn" {
+  type = string
+}
+
+resource "aws_eks_cluster" "this" {
+  name                      = var.name
+  role_arn                  = "arn:aws:iam::111111111111:role/eks"
+  enabled_cluster_log_types = ["api"]
+
+  vpc_config {
+    subnet_ids = ["subnet-12345678"]
+  }
+
+  encryption_config {
+    resources = ["secrets"]
+
+    provider {
+      key_arn = var.kms_key_arn
+    }
+  }
+}
@@ -0,0 +1,51 @@
+// tfplan.json is written by hand for this config, it is not generated by Terraform
+
+terraform {
+  required_version = ">= 1.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "= 5.34.0"
+    }
+  }
+}
+
+resource "aws_kms_key" "main" {
+  enable_key_rotation = true
+}
+
+resource "aws_kms_alias" "main" {
+  name          = "alias/main"
+  target_key_id = aws_kms_key.main.key_id
+}
+
+resource "aws_kms_key" "signing" {
+  key_usage                = "SIGN_VERIFY"
+  customer_master_key_spec = "ECC_NIST_P256"
+}
+
+data "aws_kms_key" "existing" {
+  key_id = "alias/existing"
+}
+
+resource "aws_ebs_volume" "alias" {
+  availability_zone = "us-east-1a"
+  size              = 10
+  encrypted         = true
+  kms_key_id        = aws_kms_alias.main.arn
+}
+
+resource "aws_ebs_volume" "existing" {
+  availability_zone = "us-east-1a"
+  size              = 10
+  encrypted         = true
+  kms_key_id        = data.aws_kms_key.existing.arn
+}
+
+resource "aws_ebs_volume" "existing_alias" {
+  availability_zone = "us-east-1a"
+  size              = 10
+  encrypted         = true
+  kms_key_id        = "alias/existing"
+}
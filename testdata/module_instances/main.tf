@@ -0,0 +1,27 @@
+// tfplan.json is written by hand for this config, it is not generated by Terraform
+
+terraform {
+  required_version = ">= 1.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "= 5.34.0"
+    }
+  }
+}
+
+locals {
+  versioning = {
+    a = "Enabled"
+    b = "Suspended"
+  }
+}
+
+module "b" {
+  source   = "./modules/bucket"
+  for_each = local.versioning
+
+  name              = each.key
+  versioning_status = each.value
+}
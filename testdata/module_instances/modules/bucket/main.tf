@@ -0,0 +1,19 @@
+variable "name" {
+  type = string
+}
+
+variable "versioning_status" {
+  type = string
+}
+
+resource "aws_s3_bucket" "this" {
+  bucket = "logs-${var.name}"
+}
+
+resource "aws_s3_bucket_versioning" "this" {
+  bucket = aws_s3_bucket.this.id
+
+  versioning_configuration {
+    status = var.versioning_status
+  }
+}
@@ -0,0 +1,87 @@
+// tfplan.json is written by hand for this config, it is not generated by Terraform
+
+terraform {
+  required_version = ">= 1.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "= 5.34.0"
+    }
+  }
+}
+
+variable "region" {
+  type    = string
+  default = "us-east-1"
+}
+
+provider "aws" {
+  region              = var.region
+  allowed_account_ids = ["111111111111"]
+
+  default_tags {
+    tags = {
+      Environment = "prod"
+      Team        = "platform"
+    }
+  }
+}
+
+provider "aws" {
+  alias               = "west"
+  region              = "us-west-2"
+  allowed_account_ids = ["111111111111"]
+}
+
+provider "aws" {
+  alias  = "audit"
+  region = "us-east-1"
+
+  assume_role {
+    role_arn = "arn:aws:iam::222222222222:role/terraform"
+  }
+}
+
+resource "aws_ebs_encryption_by_default" "this" {
+  enabled = true
+}
+
+resource "aws_ebs_volume" "east" {
+  availability_zone = "us-east-1a"
+  size              = 10
+}
+
+resource "aws_ebs_volume" "west" {
+  provider = aws.west
+
+  availability_zone = "us-west-2a"
+  size              = 10
+}
+
+resource "aws_ebs_volume" "audit" {
+  provider = aws.audit
+
+  availability_zone = "us-east-1a"
+  size              = 10
+}
+
+resource "aws_s3_account_public_access_block" "audit" {
+  provider = aws.audit
+
+  block_public_acls = true
+}
+
+resource "aws_s3_bucket" "main" {
+  bucket = "main"
+
+  tags = {
+    Team = "storage"
+  }
+}
+
+resource "aws_s3_bucket" "audit" {
+  provider = aws.audit
+
+  bucket = "audit"
+}
@@ -0,0 +1,91 @@
+// tfplan.json is written by hand for this config, it is not generated by Terraform
+
+terraform {
+  required_version = ">= 1.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "= 5.34.0"
+    }
+  }
+}
+
+resource "aws_kms_key" "db" {
+  enable_key_rotation = true
+}
+
+resource "aws_db_parameter_group" "postgres" {
+  name   = "postgres"
+  family = "postgres15"
+
+  parameter {
+    name  = "rds.force_ssl"
+    value = "1"
+  }
+}
+
+resource "aws_db_instance" "main" {
+  identifier                          = "main"
+  engine                              = "postgres"
+  engine_version                      = "15.4"
+  instance_class                      = "db.t3.micro"
+  allocated_storage                   = 20
+  storage_encrypted                   = true
+  kms_key_id                          = aws_kms_key.db.arn
+  backup_retention_period             = 7
+  performance_insights_enabled        = true
+  performance_insights_kms_key_id     = aws_kms_key.db.arn
+  iam_database_authentication_enabled = true
+  deletion_protection                 = true
+  multi_az                            = true
+  parameter_group_name                = aws_db_parameter_group.postgres.name
+  enabled_cloudwatch_logs_exports     = ["postgresql"]
+
+  tags = {
+    Name = "main"
+  }
+}
+
+resource "aws_db_instance" "replica" {
+  identifier          = "replica"
+  instance_class      = "db.t3.micro"
+  replicate_source_db = aws_db_instance.main.identifier
+  publicly_accessible = true
+}
+
+resource "aws_db_snapshot" "main" {
+  db_instance_identifier = aws_db_instance.main.identifier
+  db_snapshot_identifier = "main-snapshot"
+}
+
+resource "aws_rds_cluster" "aurora" {
+  cluster_identifier  = "aurora"
+  engine              = "aurora-postgresql"
+  storage_encrypted   = true
+  availability_zones  = ["us-east-1a"]
+  skip_final_snapshot = true
+}
+
+resource "aws_rds_cluster_instance" "aurora" {
+  identifier          = "aurora-1"
+  cluster_identifier  = aws_rds_cluster.aurora.id
+  instance_class      = "db.r6g.large"
+  engine              = aws_rds_cluster.aurora.engine
+  publicly_accessible = true
+}
+
+resource "aws_rds_cluster_instance" "external" {
+  identifier         = "external-1"
+  cluster_identifier = "external"
+  instance_class     = "db.r6g.large"
+  engine             = "aurora-mysql"
+}
+
+resource "aws_db_security_group" "legacy" {
+  name = "legacy"
+
+  ingress {
+    cidr = "10.0.0.0/24"
+  }
+}
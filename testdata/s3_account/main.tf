@@ -0,0 +1,43 @@
+// tfplan.json is written by hand for this config, it is not generated by Terraform
+
+terraform {
+  required_version = ">= 1.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "= 5.34.0"
+    }
+  }
+}
+
+resource "aws_s3_account_public_access_block" "this" {
+  block_public_acls   = true
+  block_public_policy = true
+}
+
+resource "aws_s3_bucket" "with_block" {
+  bucket = "with-block"
+}
+
+resource "aws_s3_bucket_public_access_block" "with_block" {
+  bucket = aws_s3_bucket.with_block.id
+
+  ignore_public_acls = true
+}
+
+resource "aws_s3_bucket" "without_block" {
+  bucket = "without-block"
+}
+
+resource "aws_s3_object" "index" {
+  bucket  = aws_s3_bucket.with_block.id
+  key     = "index.html"
+  content = "<html></html>"
+}
+
+resource "aws_s3_object" "error" {
+  bucket  = aws_s3_bucket.with_block.bucket
+  key     = "error.html"
+  content = "<html></html>"
+}
@@ -0,0 +1,71 @@
+// tfplan.json is written by hand for this config, it is not generated by Terraform
+
+terraform {
+  required_version = ">= 1.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "= 5.34.0"
+    }
+  }
+}
+
+data "aws_canonical_user_id" "current" {}
+
+resource "aws_s3_bucket" "grants" {
+  bucket = "grants"
+}
+
+resource "aws_s3_bucket_acl" "grants" {
+  bucket = aws_s3_bucket.grants.id
+
+  access_control_policy {
+    grant {
+      grantee {
+        type = "Group"
+        uri  = "http://acs.amazonaws.com/groups/global/AllUsers"
+      }
+      permission = "READ"
+    }
+
+    grant {
+      grantee {
+        id   = data.aws_canonical_user_id.current.id
+        type = "CanonicalUser"
+      }
+      permission = "FULL_CONTROL"
+    }
+
+    owner {
+      id = data.aws_canonical_user_id.current.id
+    }
+  }
+}
+
+resource "aws_s3_bucket" "legacy" {
+  bucket = "legacy"
+
+  grant {
+    type        = "Group"
+    uri         = "http://acs.amazonaws.com/groups/global/AllUsers"
+    permissions = ["READ", "WRITE"]
+  }
+}
+
+resource "aws_s3_bucket" "enforced" {
+  bucket = "enforced"
+}
+
+resource "aws_s3_bucket_ownership_controls" "enforced" {
+  bucket = aws_s3_bucket.enforced.id
+
+  rule {
+    object_ownership = "BucketOwnerEnforced"
+  }
+}
+
+resource "aws_s3_bucket_acl" "enforced" {
+  bucket = aws_s3_bucket.enforced.id
+  acl    = "public-read"
+}
@@ -0,0 +1,141 @@
+// tfplan.json is written by hand for this config, it is not generated by Terraform
+
+terraform {
+  required_version = ">= 1.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "= 5.34.0"
+    }
+  }
+}
+
+resource "aws_s3_bucket" "source" {
+  bucket              = "source"
+  object_lock_enabled = true
+
+  lifecycle_rule {
+    id                                     = "logs"
+    enabled                                = true
+    prefix                                 = "logs/"
+    abort_incomplete_multipart_upload_days = 7
+
+    tags = {
+      autoclean = "true"
+    }
+
+    expiration {
+      days = 90
+    }
+
+    noncurrent_version_expiration {
+      days = 30
+    }
+  }
+}
+
+resource "aws_s3_bucket_object_lock_configuration" "source" {
+  bucket = aws_s3_bucket.source.id
+
+  rule {
+    default_retention {
+      mode = "COMPLIANCE"
+      days = 5
+    }
+  }
+}
+
+resource "aws_s3_bucket" "replica" {
+  bucket = "replica"
+}
+
+resource "aws_s3_bucket_replication_configuration" "source" {
+  bucket = aws_s3_bucket.source.id
+  role   = "arn:aws:iam::123456789012:role/replication"
+
+  rule {
+    id     = "all"
+    status = "Enabled"
+
+    filter {
+      prefix = "data/"
+    }
+
+    delete_marker_replication {
+      status = "Disabled"
+    }
+
+    destination {
+      bucket        = aws_s3_bucket.replica.arn
+      storage_class = "STANDARD"
+    }
+  }
+
+  rule {
+    id       = "tagged"
+    status   = "Enabled"
+    priority = 1
+
+    filter {
+      tag {
+        key   = "replicate"
+        value = "true"
+      }
+    }
+
+    delete_marker_replication {
+      status = "Disabled"
+    }
+
+    destination {
+      bucket        = aws_s3_bucket.replica.arn
+      storage_class = "GLACIER"
+    }
+  }
+}
+
+resource "aws_s3_bucket_lifecycle_configuration" "replica" {
+  bucket = aws_s3_bucket.replica.id
+
+  rule {
+    id     = "expire"
+    status = "Enabled"
+
+    filter {
+      prefix = "tmp/"
+    }
+
+    expiration {
+      days = 1
+    }
+
+    noncurrent_version_expiration {
+      noncurrent_days = 1
+    }
+
+    abort_incomplete_multipart_upload {
+      days_after_initiation = 1
+    }
+  }
+
+  rule {
+    id     = "archive"
+    status = "Enabled"
+
+    filter {
+      and {
+        prefix                   = "archive/"
+        object_size_greater_than = 1024
+
+        tags = {
+          class = "cold"
+        }
+      }
+    }
+
+    expiration {
+      days = 365
+    }
+  }
+}
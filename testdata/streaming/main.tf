@@ -0,0 +1,114 @@
+// tfplan.json is written by hand for this config, it is not generated by Terraform
+
+terraform {
+  required_version = ">= 1.0"
+
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "= 5.34.0"
+    }
+  }
+}
+
+resource "aws_kms_key" "streaming" {
+  description         = "streaming"
+  enable_key_rotation = true
+}
+
+resource "aws_efs_file_system" "encrypted" {
+  creation_token = "encrypted"
+  encrypted      = true
+  kms_key_id     = aws_kms_key.streaming.arn
+}
+
+resource "aws_efs_file_system" "plain" {
+  creation_token = "plain"
+}
+
+resource "aws_kinesis_stream" "events" {
+  name            = "events"
+  shard_count     = 1
+  encryption_type = "KMS"
+  kms_key_id      = aws_kms_key.streaming.id
+}
+
+resource "aws_kinesis_stream" "clicks" {
+  name        = "clicks"
+  shard_count = 1
+}
+
+resource "aws_cloudwatch_log_group" "kafka" {
+  name = "kafka"
+}
+
+resource "aws_msk_cluster" "kafka" {
+  cluster_name           = "kafka"
+  kafka_version          = "3.5.1"
+  number_of_broker_nodes = 3
+
+  broker_node_group_info {
+    instance_type   = "kafka.m5.large"
+    client_subnets  = ["subnet-1", "subnet-2", "subnet-3"]
+    security_groups = ["sg-1"]
+  }
+
+  encryption_info {
+    encryption_at_rest_kms_key_arn = aws_kms_key.streaming.arn
+
+    encryption_in_transit {
+      client_broker = "TLS_PLAINTEXT"
+    }
+  }
+
+  logging_info {
+    broker_logs {
+      cloudwatch_logs {
+        enabled   = true
+        log_group = aws_cloudwatch_log_group.kafka.name
+      }
+    }
+  }
+}
+
+resource "aws_msk_cluster" "default" {
+  cluster_name           = "default"
+  kafka_version          = "3.5.1"
+  number_of_broker_nodes = 3
+
+  broker_node_group_info {
+    instance_type   = "kafka.m5.large"
+    client_subnets  = ["subnet-1", "subnet-2", "subnet-3"]
+    security_groups = ["sg-1"]
+  }
+}
+
+resource "aws_mq_broker" "public" {
+  broker_name         = "public"
+  engine_type         = "ActiveMQ"
+  engine_version      = "5.17.6"
+  host_instance_type  = "mq.t3.micro"
+  publicly_accessible = true
+
+  user {
+    username = "admin"
+    password = "password123456"
+  }
+}
+
+resource "aws_mq_broker" "private" {
+  broker_name        = "private"
+  engine_type        = "ActiveMQ"
+  engine_version     = "5.17.6"
+  host_instance_type = "mq.t3.micro"
+
+  logs {
+    general = true
+    audit   = true
+  }
+
+  user {
+    username = "admin"
+    password = "password123456"
+  }
+}
@@ -0,0 +1,280 @@
+package tfplanadapt
+
+import (
+	"github.com/aquasecurity/defsec/pkg/providers/aws/ec2"
+	"github.com/aquasecurity/defsec/pkg/types"
+)
+
+func adaptVPCs(g *Graph) []ec2.VPC {
+	var vpcs []ec2.VPC
+	for _, vpcType := range []string{"aws_vpc", "aws_default_vpc"} {
+		for _, res := range g.FindResourcesByType(vpcType) {
+			vpc := ec2.VPC{
+				Metadata:        res.Metadata(),
+				ID:              res.GetStringAttr("id"),
+				IsDefault:       types.Bool(vpcType == "aws_default_vpc", res.Metadata()),
+				FlowLogsEnabled: types.BoolDefault(false, res.Metadata()),
+			}
+
+			if flowLog := res.FindBackRelated("aws_flow_log", "vpc_id", "id"); flowLog != nil {
+				vpc.FlowLogsEnabled = types.Bool(true, flowLog.Metadata())
+			}
+
+			for _, groupType := range []string{"aws_security_group", "aws_default_security_group"} {
+				for _, group := range res.FindAllBackRelated(groupType, "vpc_id", "id") {
+					vpc.SecurityGroups = append(vpc.SecurityGroups, adaptNestedSecurityGroup(group))
+				}
+			}
+
+			vpcs = append(vpcs, vpc)
+		}
+	}
+	return vpcs
+}
+
+func adaptSubnets(g *Graph) []ec2.Subnet {
+	var subnets []ec2.Subnet
+	for _, subnetType := range []string{"aws_subnet", "aws_default_subnet"} {
+		for _, res := range g.FindResourcesByType(subnetType) {
+			subnets = append(subnets, ec2.Subnet{
+				Metadata:            res.Metadata(),
+				MapPublicIpOnLaunch: res.GetBoolAttr("map_public_ip_on_launch"),
+			})
+		}
+	}
+	return subnets
+}
+
+var securityGroupRuleTypes = []string{
+	"aws_security_group_rule",
+	"aws_vpc_security_group_ingress_rule",
+	"aws_vpc_security_group_egress_rule",
+}
+
+func adaptSecurityGroups(g *Graph) []ec2.SecurityGroup {
+	adoptedRules := make(map[string]struct{})
+
+	var groups []ec2.SecurityGroup
+	for _, groupType := range []string{"aws_security_group", "aws_default_security_group"} {
+		for _, res := range g.FindResourcesByType(groupType) {
+			groups = append(groups, adaptSecurityGroup(res, adoptedRules))
+		}
+	}
+
+	// rules of security groups that are not managed by the plan
+	for _, ruleType := range securityGroupRuleTypes {
+		for _, ruleRes := range g.FindResourcesByType(ruleType) {
+			if _, adopted := adoptedRules[ruleRes.ID()]; adopted {
+				continue
+			}
+
+			// only the rule is managed by the plan
+			metadata := types.NewUnmanagedMetadata()
+			group := ec2.SecurityGroup{
+				Metadata:    metadata,
+				IsDefault:   types.BoolDefault(false, metadata),
+				Description: types.StringDefault("", metadata),
+				VPCID:       types.StringDefault("", metadata),
+			}
+			addSecurityGroupRule(&group, ruleRes)
+			groups = append(groups, group)
+		}
+	}
+
+	return groups
+}
+
+// adaptSecurityGroup adapts the security group with inline rules and rules defined by separate resources.
+// The addresses of the separate rules are added to adoptedRules
+func adaptSecurityGroup(res *Node, adoptedRules map[string]struct{}) ec2.SecurityGroup {
+	group := ec2.SecurityGroup{
+		Metadata:    res.Metadata(),
+		IsDefault:   types.Bool(res.resourceType == "aws_default_security_group", res.Metadata()),
+		Description: res.GetStringAttr("description"),
+		VPCID:       res.GetStringAttr("vpc_id"),
+	}
+
+	for _, rule := range res.GetAttr("ingress").ToList() {
+		group.IngressRules = append(group.IngressRules, adaptInlineSecurityGroupRule(rule))
+	}
+
+	for _, rule := range res.GetAttr("egress").ToList() {
+		group.EgressRules = append(group.EgressRules, adaptInlineSecurityGroupRule(rule))
+	}
+
+	for _, ruleType := range securityGroupRuleTypes {
+		for _, ruleRes := range res.FindAllBackRelated(ruleType, "security_group_id", "id") {
+			addSecurityGroupRule(&group, ruleRes)
+			if adoptedRules != nil {
+				adoptedRules[ruleRes.ID()] = struct{}{}
+			}
+		}
+	}
+
+	return group
+}
+
+// adaptNestedSecurityGroup adapts the copy of the group that is nested in a VPC or an instance.
+// The group itself is adapted under EC2.SecurityGroups, so the copy is unmanaged
+// to keep its findings from being reported twice
+func adaptNestedSecurityGroup(res *Node) ec2.SecurityGroup {
+	group := adaptSecurityGroup(res, nil)
+
+	metadata := types.NewUnmanagedMetadata()
+	return ec2.SecurityGroup{
+		Metadata:     metadata,
+		IsDefault:    types.Bool(group.IsDefault.Value(), metadata),
+		Description:  types.String(group.Description.Value(), metadata),
+		VPCID:        types.String(group.VPCID.Value(), metadata),
+		IngressRules: unmanagedSecurityGroupRules(group.IngressRules, metadata),
+		EgressRules:  unmanagedSecurityGroupRules(group.EgressRules, metadata),
+	}
+}
+
+func unmanagedSecurityGroupRules(rules []ec2.SecurityGroupRule, metadata types.Metadata) []ec2.SecurityGroupRule {
+	var result []ec2.SecurityGroupRule
+	for _, rule := range rules {
+		var cidrs []types.StringValue
+		for _, cidr := range rule.CIDRs {
+			cidrs = append(cidrs, types.String(cidr.Value(), metadata))
+		}
+		result = append(result, ec2.SecurityGroupRule{
+			Metadata:    metadata,
+			Description: types.String(rule.Description.Value(), metadata),
+			CIDRs:       cidrs,
+		})
+	}
+	return result
+}
+
+func adaptInlineSecurityGroupRule(rule *Attribute) ec2.SecurityGroupRule {
+	var cidrs []types.StringValue
+	for _, attrName := range []string{"cidr_blocks", "ipv6_cidr_blocks"} {
+		for _, cidr := range rule.GetNestedAttr(attrName).AsStrings() {
+			cidrs = append(cidrs, types.String(cidr, rule.Metadata()))
+		}
+	}
+
+	return ec2.SecurityGroupRule{
+		Metadata:    rule.Metadata(),
+		Description: rule.GetStringAttr("description"),
+		CIDRs:       cidrs,
+	}
+}
+
+func addSecurityGroupRule(group *ec2.SecurityGroup, ruleRes *Node) {
+	var cidrs []types.StringValue
+	for _, attrName := range []string{"cidr_blocks", "ipv6_cidr_blocks"} {
+		for _, cidr := range ruleRes.GetAttr(attrName).AsStrings() {
+			cidrs = append(cidrs, types.String(cidr, ruleRes.Metadata()))
+		}
+	}
+
+	for _, attrName := range []string{"cidr_ipv4", "cidr_ipv6"} {
+		if cidr := ruleRes.GetStringAttr(attrName); cidr.IsNotEmpty() {
+			cidrs = append(cidrs, cidr)
+		}
+	}
+
+	rule := ec2.SecurityGroupRule{
+		Metadata:    ruleRes.Metadata(),
+		Description: ruleRes.GetStringAttr("description"),
+		CIDRs:       cidrs,
+	}
+
+	switch ruleRes.resourceType {
+	case "aws_vpc_security_group_ingress_rule":
+		group.IngressRules = append(group.IngressRules, rule)
+	case "aws_vpc_security_group_egress_rule":
+		group.EgressRules = append(group.EgressRules, rule)
+	default:
+		if ruleRes.GetStringAttr("type").EqualTo(ec2.TypeEgress) {
+			group.EgressRules = append(group.EgressRules, rule)
+		} else {
+			group.IngressRules = append(group.IngressRules, rule)
+		}
+	}
+}
+
+func adaptNetworkACLs(g *Graph) []ec2.NetworkACL {
+	adoptedRules := make(map[string]struct{})
+
+	var acls []ec2.NetworkACL
+	for _, aclType := range []string{"aws_network_acl", "aws_default_network_acl"} {
+		for _, res := range g.FindResourcesByType(aclType) {
+			acl := ec2.NetworkACL{
+				Metadata:      res.Metadata(),
+				IsDefaultRule: types.Bool(aclType == "aws_default_network_acl", res.Metadata()),
+			}
+
+			for _, rule := range res.GetAttr("ingress").ToList() {
+				acl.Rules = append(acl.Rules, adaptInlineNetworkACLRule(rule, ec2.TypeIngress))
+			}
+
+			for _, rule := range res.GetAttr("egress").ToList() {
+				acl.Rules = append(acl.Rules, adaptInlineNetworkACLRule(rule, ec2.TypeEgress))
+			}
+
+			for _, ruleRes := range res.FindAllBackRelated("aws_network_acl_rule", "network_acl_id", "id") {
+				acl.Rules = append(acl.Rules, adaptNetworkACLRule(ruleRes))
+				adoptedRules[ruleRes.ID()] = struct{}{}
+			}
+
+			acls = append(acls, acl)
+		}
+	}
+
+	// rules of network ACLs that are not managed by the plan
+	for _, ruleRes := range g.FindResourcesByType("aws_network_acl_rule") {
+		if _, adopted := adoptedRules[ruleRes.ID()]; adopted {
+			continue
+		}
+		metadata := types.NewUnmanagedMetadata()
+		acls = append(acls, ec2.NetworkACL{
+			Metadata:      metadata,
+			IsDefaultRule: types.BoolDefault(false, metadata),
+			Rules:         []ec2.NetworkACLRule{adaptNetworkACLRule(ruleRes)},
+		})
+	}
+
+	return acls
+}
+
+func adaptInlineNetworkACLRule(rule *Attribute, typ string) ec2.NetworkACLRule {
+	var cidrs []types.StringValue
+	for _, attrName := range []string{"cidr_block", "ipv6_cidr_block"} {
+		if cidr := rule.GetStringAttr(attrName); cidr.IsNotEmpty() {
+			cidrs = append(cidrs, cidr)
+		}
+	}
+
+	return ec2.NetworkACLRule{
+		Metadata: rule.Metadata(),
+		Type:     types.String(typ, rule.Metadata()),
+		Action:   rule.GetStringAttr("action"),
+		Protocol: rule.GetStringAttr("protocol"),
+		CIDRs:    cidrs,
+	}
+}
+
+func adaptNetworkACLRule(res *Node) ec2.NetworkACLRule {
+	var cidrs []types.StringValue
+	for _, attrName := range []string{"cidr_block", "ipv6_cidr_block"} {
+		if cidr := res.GetStringAttr(attrName); cidr.IsNotEmpty() {
+			cidrs = append(cidrs, cidr)
+		}
+	}
+
+	typ := ec2.TypeIngress
+	if res.GetBoolAttr("egress").IsTrue() {
+		typ = ec2.TypeEgress
+	}
+
+	return ec2.NetworkACLRule{
+		Metadata: res.Metadata(),
+		Type:     types.String(typ, res.Metadata()),
+		Action:   res.GetStringAttr("rule_action"),
+		Protocol: res.GetStringAttr("protocol"),
+		CIDRs:    cidrs,
+	}
+}